@@ -0,0 +1,121 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type aggregateGrid struct {
+	Id       int `db:"f.id" grid:"filter,sort"`
+	TagCount int `db:"tagCount" grid:"filter,sort,aggregate=COUNT(t.id)"`
+}
+
+func (T aggregateGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("file as f").
+		LeftJoin("tag as t ON f.id = t.file_id").
+		GroupBy("f.id")
+}
+
+func Test_AggregateSelects(t *testing.T) {
+	sql, _, err := createSelects(aggregateGrid{}, MySQL, "", nil).ToSql()
+	require.Nil(t, err)
+	assert.Equal(
+		t,
+		"SELECT `f`.`id` as `f.id`, COUNT(t.id) as `tagCount` FROM file as f LEFT JOIN tag as t ON f.id = t.file_id GROUP BY f.id",
+		sql,
+	)
+}
+
+type expressionGrid struct {
+	Id      int     `db:"f.id" grid:"filter,sort"`
+	Weight  float64 `db:"weight" grid:"filter,aggregate=ROUND(COALESCE(AVG(t.weight), 0), 2),sort"`
+	Invalid int     `db:"invalid" grid:"filter,aggregate=SUM(t.weight))"`
+}
+
+func (T expressionGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("file as f").
+		LeftJoin("tag as t ON f.id = t.file_id").
+		GroupBy("f.id")
+}
+
+func Test_AggregateExpressions(t *testing.T) {
+	column, safe, err := columnExpression(MySQL, expressionGrid{}, "weight")
+	require.Nil(t, err)
+	assert.False(t, safe)
+	assert.Equal(t, "ROUND(COALESCE(AVG(t.weight), 0), 2)", column)
+	assert.True(t, hasTag(expressionGrid{}, "weight", sortable))
+
+	_, _, err = columnExpression(MySQL, expressionGrid{}, "invalid")
+	assert.EqualError(t, err, "invalid aggregate [SUM(t.weight))] of field [invalid]")
+}
+
+func Test_AggregateGrid(t *testing.T) {
+	prepareTestData(t)
+
+	dto := GridDto{
+		Filter: [][]Filter{
+			{
+				{
+					Column:   "tagCount",
+					Operator: "GTE",
+					Value:    []string{"1"},
+				},
+			},
+		},
+		Sorter: nil,
+		Paging: Paging{},
+		Search: "",
+		Items:  nil,
+	}
+
+	var res []aggregateGrid
+	dto, err := GetData(aggregateGrid{}, dto, MariaDB, &res)
+	require.Nil(t, err)
+
+	assert.Equal(t, 1, dto.Paging.Total)
+	assert.Equal(t, 2, res[0].TagCount)
+
+	dto = GridDto{
+		Filter: [][]Filter{},
+		Sorter: []Sorter{
+			{
+				Column:    "tagCount",
+				Direction: "ASC",
+			},
+		},
+		Paging: Paging{},
+		Search: "",
+		Items:  nil,
+	}
+
+	res = make([]aggregateGrid, 0)
+	dto, err = GetData(aggregateGrid{}, dto, MariaDB, &res)
+	require.Nil(t, err)
+
+	assert.Equal(t, 2, dto.Paging.Total)
+	assert.Equal(t, 2, res[0].Id)
+	assert.Equal(t, 0, res[0].TagCount)
+
+	dto = GridDto{
+		Filter: [][]Filter{
+			{
+				{
+					Column:   "tagCount",
+					Operator: "GTE",
+					Value:    []string{"1"},
+				},
+				{
+					Column:   "id",
+					Operator: "EQ",
+					Value:    []string{"2"},
+				},
+			},
+		},
+	}
+
+	_, err = GetData(aggregateGrid{}, dto, MariaDB, &res)
+	assert.EqualError(t, err, "aggregated fields can't be grouped with other filters")
+}
@@ -0,0 +1,172 @@
+package filter
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache of counts used by GetData, counts are not cached when nil
+var Counts CountCache
+
+type CachedCount struct {
+	Total     int
+	Estimated bool
+}
+
+/// Counts keyed by grid type and normalized filters and search
+type CountCache interface {
+	Get(grid, key string) (CachedCount, bool)
+	Set(grid, key string, count CachedCount)
+	// Drops all counts of grid type
+	Invalidate(grid string)
+}
+
+// Drops cached counts of grid, call it whenever rows of the grid change
+func InvalidateCounts(model Grid) {
+	if Counts != nil {
+		Counts.Invalidate(gridType(model))
+	}
+}
+
+// Counts within transaction (snapshot mode, caller's transaction) are not cached, it may see rows others don't
+func cachedCount(ctx context.Context, model Grid, dto GridDto, db Executor, query CountQuery) (int, bool, error) {
+	strategy := countStrategy(model)
+	if _, pool := db.(txBeginner); Counts == nil || !pool {
+		return strategy.Count(ctx, db, query)
+	}
+
+	grid, key := gridType(model), countKey(dto)
+	if count, ok := Counts.Get(grid, key); ok {
+		return count.Total, count.Estimated, nil
+	}
+
+	total, estimated, err := strategy.Count(ctx, db, query)
+	if err != nil {
+		return 0, false, err
+	}
+	Counts.Set(grid, key, CachedCount{Total: total, Estimated: estimated})
+
+	return total, estimated, nil
+}
+
+func gridType(model Grid) string {
+	fType := reflect.TypeOf(model)
+
+	return fType.PkgPath() + "." + fType.Name()
+}
+
+// Filters within group and groups are sorted, so their order doesn't matter
+func countKey(dto GridDto) string {
+	groups := make([]string, 0, len(dto.Filter))
+	for _, filters := range dto.Filter {
+		items := make([]string, 0, len(filters))
+		for _, filter := range filters {
+			raw, _ := json.Marshal(filter)
+			items = append(items, string(raw))
+		}
+
+		if len(items) > 0 {
+			sort.Strings(items)
+			groups = append(groups, strings.Join(items, ","))
+		}
+	}
+	sort.Strings(groups)
+
+	raw, _ := json.Marshal(map[string]interface{}{
+		"filter":   groups,
+		"search":   strings.Join(strings.Fields(dto.Search), " "),
+		"timezone": dto.Timezone,
+	})
+
+	return string(raw)
+}
+
+// In-memory CountCache dropping least recently used counts over its size and counts older than ttl
+type MemoryCountCache struct {
+	size    int
+	ttl     time.Duration
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	recent  *list.List
+}
+
+type countEntry struct {
+	grid    string
+	key     string
+	count   CachedCount
+	expires time.Time
+}
+
+func NewMemoryCountCache(size int, ttl time.Duration) *MemoryCountCache {
+	return &MemoryCountCache{
+		size:    size,
+		ttl:     ttl,
+		entries: map[string]*list.Element{},
+		recent:  list.New(),
+	}
+}
+
+func (c *MemoryCountCache) Get(grid, key string) (CachedCount, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[grid+"\x00"+key]
+	if !ok {
+		return CachedCount{}, false
+	}
+
+	entry := element.Value.(*countEntry)
+	if !Clock().Before(entry.expires) {
+		c.remove(element)
+
+		return CachedCount{}, false
+	}
+
+	c.recent.MoveToFront(element)
+
+	return entry.count, true
+}
+
+func (c *MemoryCountCache) Set(grid, key string, count CachedCount) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[grid+"\x00"+key]; ok {
+		c.remove(element)
+	}
+
+	c.entries[grid+"\x00"+key] = c.recent.PushFront(&countEntry{
+		grid:    grid,
+		key:     key,
+		count:   count,
+		expires: Clock().Add(c.ttl),
+	})
+
+	for c.size > 0 && c.recent.Len() > c.size {
+		c.remove(c.recent.Back())
+	}
+}
+
+func (c *MemoryCountCache) Invalidate(grid string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for element := c.recent.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*countEntry).grid == grid {
+			c.remove(element)
+		}
+		element = next
+	}
+}
+
+func (c *MemoryCountCache) remove(element *list.Element) {
+	entry := c.recent.Remove(element).(*countEntry)
+	delete(c.entries, entry.grid+"\x00"+entry.key)
+}
@@ -0,0 +1,73 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CountKey(t *testing.T) {
+	status := Filter{Column: "status", Operator: In, Value: []string{"new", "open"}}
+	name := Filter{Column: "name", Operator: Like, Value: []string{"losos"}}
+	id := Filter{Column: "id", Operator: Gt, Value: []string{"1"}}
+
+	assert.Equal(
+		t,
+		countKey(GridDto{Filter: [][]Filter{{status, name}, {id}}, Search: "big  fish "}),
+		countKey(GridDto{Filter: [][]Filter{{}, {id}, {name, status}}, Search: "big fish", Paging: Paging{Page: 3}}),
+	)
+	assert.NotEqual(
+		t,
+		countKey(GridDto{Filter: [][]Filter{{status, name}, {id}}}),
+		countKey(GridDto{Filter: [][]Filter{{status}, {name}, {id}}}),
+	)
+	assert.NotEqual(t, countKey(GridDto{Search: "fish"}), countKey(GridDto{Search: "fish", Timezone: "UTC"}))
+	assert.Equal(t, "github.com/hanaboso/go-filter/pkg/filter.sortGrid", gridType(sortGrid{}))
+}
+
+func Test_MemoryCountCache(t *testing.T) {
+	now := time.Date(2024, 3, 14, 15, 30, 0, 0, time.UTC)
+	clock := Clock
+	Clock = func() time.Time { return now }
+	t.Cleanup(func() {
+		Clock = clock
+	})
+
+	cache := NewMemoryCountCache(2, time.Minute)
+	cache.Set("orders", "a", CachedCount{Total: 1})
+	cache.Set("orders", "b", CachedCount{Total: 2})
+
+	count, ok := cache.Get("orders", "a")
+	assert.True(t, ok)
+	assert.Equal(t, CachedCount{Total: 1}, count)
+
+	// Least recently used count is dropped
+	cache.Set("users", "a", CachedCount{Total: 3, Estimated: true})
+	_, ok = cache.Get("orders", "b")
+	assert.False(t, ok)
+
+	count, ok = cache.Get("users", "a")
+	assert.True(t, ok)
+	assert.Equal(t, CachedCount{Total: 3, Estimated: true}, count)
+
+	cache.Invalidate("orders")
+	_, ok = cache.Get("orders", "a")
+	assert.False(t, ok)
+	_, ok = cache.Get("users", "a")
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = cache.Get("users", "a")
+	assert.False(t, ok)
+
+	Counts = NewMemoryCountCache(10, time.Minute)
+	t.Cleanup(func() {
+		Counts = nil
+	})
+
+	Counts.Set(gridType(sortGrid{}), "a", CachedCount{Total: 1})
+	InvalidateCounts(sortGrid{})
+	_, ok = Counts.Get(gridType(sortGrid{}), "a")
+	assert.False(t, ok)
+}
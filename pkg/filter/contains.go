@@ -0,0 +1,121 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const (
+	Contains    = "CONTAINS"
+	ContainsAny = "CONTAINS_ANY"
+	ContainsAll = "CONTAINS_ALL"
+
+	setColumn = "set"
+)
+
+func containment(operator string) bool {
+	return operator == Contains || operator == ContainsAny || operator == ContainsAll
+}
+
+// Membership within SET columns (FIND_IN_SET), JSON arrays (JSON_CONTAINS, @>) or PostgreSQL arrays (@>, &&)
+func containsQuery(d Dialect, model Grid, filter Filter) (squirrel.Sqlizer, error) {
+	field, ok := gridField(model, filter.Column)
+	if !ok {
+		return nil, fmt.Errorf("field [%s] is not tagged for filtering", filter.Column)
+	}
+
+	fType := field.Type
+	if fType.Kind() == reflect.Ptr {
+		fType = fType.Elem()
+	}
+
+	_, _, set := fieldTag(field, setColumn)
+	_, _, isJSON := fieldTag(field, jsonPath)
+	list := fType.Kind() == reflect.Slice || fType.Kind() == reflect.Array
+	if !set && !isJSON && !list {
+		return nil, fmt.Errorf("operator [%s] requires multi-valued field, [%s] is not", filter.Operator, filter.Column)
+	}
+	// MySQL has no array columns, slices are stored as SET or JSON
+	if d == MySQL && !set && !isJSON {
+		return nil, fmt.Errorf("operator [%s] requires set or json field on MySQL, [%s] is not", filter.Operator, filter.Column)
+	}
+	if len(filter.Value) == 0 {
+		return nil, fmt.Errorf("operator [%s] of field [%s] requires value", filter.Operator, filter.Column)
+	}
+
+	name := d.Name(taggedName(model, filter.Column), true)
+	if isJSON {
+		path, err := jsonPathOf(model, filter.Column)
+		if err != nil {
+			return nil, err
+		}
+
+		numeric := list && fType.Elem().Kind() >= reflect.Int && fType.Elem().Kind() <= reflect.Float64
+
+		return jsonContains(d, name, path, filter, numeric), nil
+	}
+
+	if d == PostgreSQL {
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(filter.Value)), ",")
+		operator := "@>"
+		if filter.Operator == ContainsAny {
+			operator = "&&"
+		}
+
+		return squirrel.Expr(fmt.Sprintf("%s %s ARRAY[%s]", name, operator, placeholders), ParseValues(filter.Value, filter.Operator)...), nil
+	}
+
+	var queries []squirrel.Sqlizer
+	for _, value := range filter.Value {
+		queries = append(queries, squirrel.Expr(fmt.Sprintf("FIND_IN_SET(?, %s) > 0", name), value))
+	}
+
+	if filter.Operator == ContainsAny {
+		return squirrel.Or(queries), nil
+	}
+
+	return squirrel.And(queries), nil
+}
+
+func jsonContains(d Dialect, name, path string, filter Filter, numeric bool) squirrel.Sqlizer {
+	encode := func(values []string) string {
+		encoded := make([]string, len(values))
+		for i, value := range values {
+			if _, err := strconv.ParseFloat(value, 64); numeric && err == nil {
+				encoded[i] = value
+			} else {
+				raw, _ := json.Marshal(value)
+				encoded[i] = string(raw)
+			}
+		}
+
+		return fmt.Sprintf("[%s]", strings.Join(encoded, ","))
+	}
+
+	query := fmt.Sprintf("JSON_CONTAINS(%s, ?)", name)
+	if path != "" {
+		query = fmt.Sprintf("JSON_CONTAINS(%s, ?, '%s')", name, path)
+	}
+	if d == PostgreSQL {
+		if path != "" {
+			name = strings.Replace(d.JSONValue(name, path), "#>>", "#>", 1)
+		}
+		query = fmt.Sprintf("(%s)::jsonb @> ?::jsonb", name)
+	}
+
+	if filter.Operator != ContainsAny {
+		return squirrel.Expr(query, encode(filter.Value))
+	}
+
+	var queries squirrel.Or
+	for _, value := range filter.Value {
+		queries = append(queries, squirrel.Expr(query, encode([]string{value})))
+	}
+
+	return queries
+}
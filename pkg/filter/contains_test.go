@@ -0,0 +1,97 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type containsGrid struct {
+	Id      int      `db:"p.id" grid:"filter"`
+	Tags    string   `db:"p.tags" grid:"filter,set"`
+	Labels  []string `db:"p.labels" grid:"filter,json"`
+	Sizes   []int    `db:"p.sizes" grid:"filter,json"`
+	Colors  []string `db:"p.colors" grid:"filter"`
+	Attrs   string   `db:"p.attrs" grid:"filter,json"`
+	Comment string   `db:"p.comment" grid:"filter"`
+}
+
+func (T containsGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("product p")
+}
+
+func Test_ContainsQuery(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		filter  Filter
+		sql     string
+		args    []interface{}
+	}{
+		{
+			MySQL,
+			Filter{Column: "tags", Operator: ContainsAny, Value: []string{"a", "b"}},
+			"(FIND_IN_SET(?, `p`.`tags`) > 0 OR FIND_IN_SET(?, `p`.`tags`) > 0)",
+			[]interface{}{"a", "b"},
+		},
+		{
+			MySQL,
+			Filter{Column: "tags", Operator: ContainsAll, Value: []string{"a", "b"}},
+			"(FIND_IN_SET(?, `p`.`tags`) > 0 AND FIND_IN_SET(?, `p`.`tags`) > 0)",
+			[]interface{}{"a", "b"},
+		},
+		{
+			MySQL,
+			Filter{Column: "labels", Operator: Contains, Value: []string{"new"}},
+			"JSON_CONTAINS(`p`.`labels`, ?)",
+			[]interface{}{`["new"]`},
+		},
+		{
+			MySQL,
+			Filter{Column: "sizes", Operator: ContainsAny, Value: []string{"38", "40"}},
+			"(JSON_CONTAINS(`p`.`sizes`, ?) OR JSON_CONTAINS(`p`.`sizes`, ?))",
+			[]interface{}{"[38]", "[40]"},
+		},
+		{
+			MySQL,
+			Filter{Column: "attrs.colors", Operator: ContainsAll, Value: []string{"red", "blue"}},
+			"JSON_CONTAINS(`p`.`attrs`, ?, '$.colors')",
+			[]interface{}{`["red","blue"]`},
+		},
+		{
+			PostgreSQL,
+			Filter{Column: "colors", Operator: ContainsAny, Value: []string{"red", "blue"}},
+			`"p"."colors" && ARRAY[?,?]`,
+			[]interface{}{"red", "blue"},
+		},
+		{
+			PostgreSQL,
+			Filter{Column: "colors", Operator: Contains, Value: []string{"red"}},
+			`"p"."colors" @> ARRAY[?]`,
+			[]interface{}{"red"},
+		},
+		{
+			PostgreSQL,
+			Filter{Column: "attrs.colors", Operator: Contains, Value: []string{"red"}},
+			`("p"."attrs" #> '{colors}')::jsonb @> ?::jsonb`,
+			[]interface{}{`["red"]`},
+		},
+	}
+
+	for _, test := range tests {
+		query, err := containsQuery(test.dialect, containsGrid{}, test.filter)
+		require.Nil(t, err)
+
+		sql, args, err := query.ToSql()
+		require.Nil(t, err)
+		assert.Equal(t, test.sql, sql)
+		assert.Equal(t, test.args, args)
+	}
+
+	_, err := containsQuery(MySQL, containsGrid{}, Filter{Column: "comment", Operator: Contains, Value: []string{"a"}})
+	assert.EqualError(t, err, "operator [CONTAINS] requires multi-valued field, [comment] is not")
+
+	_, err = containsQuery(MySQL, containsGrid{}, Filter{Column: "colors", Operator: Contains, Value: []string{"red"}})
+	assert.EqualError(t, err, "operator [CONTAINS] requires set or json field on MySQL, [colors] is not")
+}
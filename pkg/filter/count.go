@@ -0,0 +1,172 @@
+package filter
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// Capped count stops counting at this number of rows unless its Limit is set
+var CountLimit = 10000
+
+type CountQuery struct {
+	Dialect Dialect
+	// Exact SELECT COUNT(*) of filtered rows
+	Sql  string
+	Args []interface{}
+	// Filtered rows without ordering and paging
+	Rows squirrel.SelectBuilder
+	// Whether any filter or search applies
+	Filtered bool
+}
+
+/// Counts rows of grid, estimated count is reported within paging
+type CountStrategy interface {
+	Count(ctx context.Context, db Executor, query CountQuery) (total int, estimated bool, err error)
+}
+
+/// Grids counted by other strategy than ExactCount
+type CountStrategies interface {
+	CountStrategy() CountStrategy
+}
+
+/// Grids skipping the count query, paging then tells only whether more rows follow
+type CountSkipper interface {
+	SkipCount() bool
+}
+
+type ExactCount struct{}
+
+func (c ExactCount) Count(ctx context.Context, db Executor, query CountQuery) (int, bool, error) {
+	var count []int
+	if err := sqlx.SelectContext(ctx, db, &count, query.Sql, query.Args...); err != nil {
+		return 0, false, err
+	}
+
+	return count[0], false, nil
+}
+
+// Counts at most Limit (CountLimit when not set) rows, more of them are reported as estimated Limit
+type CappedCount struct {
+	Limit int
+}
+
+func (c CappedCount) Count(ctx context.Context, db Executor, query CountQuery) (int, bool, error) {
+	limit := c.Limit
+	if limit <= 0 {
+		limit = CountLimit
+	}
+
+	capped, args, err := squirrel.Select("COUNT(*)").
+		PlaceholderFormat(query.Dialect.placeholder()).
+		FromSelect(query.Rows.Limit(uint64(limit+1)), "capped").
+		ToSql()
+	if err != nil {
+		return 0, false, err
+	}
+
+	var count []int
+	if err = sqlx.SelectContext(ctx, db, &count, capped, args...); err != nil {
+		return 0, false, err
+	}
+
+	if count[0] > limit {
+		return limit, true, nil
+	}
+
+	return count[0], false, nil
+}
+
+// Reads row count of unfiltered table from statistics (information_schema.TABLES, pg_class.reltuples),
+// filtered rows are counted by Fallback (ExactCount when not set)
+type TableEstimate struct {
+	// Table name, may be prefixed by schema
+	Table    string
+	Fallback CountStrategy
+}
+
+func (c TableEstimate) Count(ctx context.Context, db Executor, query CountQuery) (int, bool, error) {
+	fallback := c.Fallback
+	if fallback == nil {
+		fallback = ExactCount{}
+	}
+	if query.Filtered {
+		return fallback.Count(ctx, db, query)
+	}
+
+	estimate, args := estimateQuery(query.Dialect, c.Table)
+	var count []sql.NullInt64
+	if err := sqlx.SelectContext(ctx, db, &count, estimate, args...); err != nil {
+		return 0, false, err
+	}
+
+	// Missing, never analyzed table or view
+	if len(count) == 0 || !count[0].Valid || count[0].Int64 < 0 {
+		return fallback.Count(ctx, db, query)
+	}
+
+	return int(count[0].Int64), true, nil
+}
+
+func estimateQuery(d Dialect, table string) (string, []interface{}) {
+	if d == PostgreSQL {
+		return "SELECT reltuples::bigint FROM pg_class WHERE oid = to_regclass($1)", []interface{}{table}
+	}
+
+	parts := strings.SplitN(table, ".", 2)
+	if len(parts) == 2 {
+		return "SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?",
+			[]interface{}{parts[0], parts[1]}
+	}
+
+	return "SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?", []interface{}{table}
+}
+
+func countStrategy(model Grid) CountStrategy {
+	if cs, ok := interface{}(model).(CountStrategies); ok && cs.CountStrategy() != nil {
+		return cs.CountStrategy()
+	}
+
+	return ExactCount{}
+}
+
+func skipCount(model Grid, dto GridDto) bool {
+	if cs, ok := interface{}(model).(CountSkipper); ok && cs.SkipCount() {
+		return true
+	}
+
+	return dto.SkipCount
+}
+
+// Page is fetched with one extra row telling whether more rows follow, the row is cut off
+func cutExtraRow(resultSet interface{}, size int) bool {
+	rows := reflect.Indirect(reflect.ValueOf(resultSet))
+	if rows.Kind() != reflect.Slice || rows.Len() <= size {
+		return false
+	}
+
+	rows.Set(rows.Slice(0, size))
+
+	return true
+}
+
+// Paging without total, next page exists only when more rows follow
+func uncountedPaging(paging Paging, hasMore bool) Paging {
+	paging.HasMore = hasMore
+
+	paging.PreviousPage = paging.Page - 1
+	if paging.PreviousPage <= 0 {
+		paging.PreviousPage = 1
+	}
+
+	paging.NextPage = paging.Page
+	if hasMore {
+		paging.NextPage++
+	}
+
+	return paging
+}
@@ -0,0 +1,146 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type uncountedGrid struct {
+	Id int `db:"t.id" grid:"filter,sort"`
+}
+
+func (T uncountedGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("tag as t")
+}
+
+func (T uncountedGrid) SkipCount() bool {
+	return true
+}
+
+func Test_UncountedPaging(t *testing.T) {
+	assert.True(t, skipCount(uncountedGrid{}, GridDto{}))
+	assert.True(t, skipCount(loaderGrid{}, GridDto{SkipCount: true}))
+	assert.False(t, skipCount(loaderGrid{}, GridDto{}))
+
+	rows := []uncountedGrid{{Id: 1}, {Id: 2}, {Id: 3}}
+	assert.True(t, cutExtraRow(&rows, 2))
+	assert.Equal(t, []uncountedGrid{{Id: 1}, {Id: 2}}, rows)
+	assert.False(t, cutExtraRow(&rows, 2))
+
+	assert.Equal(t, Paging{Page: 1, Size: 2, PreviousPage: 1, NextPage: 2, HasMore: true}, uncountedPaging(Paging{Page: 1, Size: 2}, true))
+	assert.Equal(t, Paging{Page: 3, Size: 2, PreviousPage: 2, NextPage: 3}, uncountedPaging(Paging{Page: 3, Size: 2}, false))
+}
+
+func Test_UncountedGrid(t *testing.T) {
+	prepareTestData(t)
+
+	var res []uncountedGrid
+	dto, err := GetData(uncountedGrid{}, GridDto{Paging: Paging{Page: 1, Size: 1}}, MariaDB, &res)
+	require.Nil(t, err)
+
+	assert.Equal(t, Paging{Page: 1, Size: 1, PreviousPage: 1, NextPage: 2, HasMore: true}, dto.Paging)
+	assert.Equal(t, []uncountedGrid{{Id: 1}}, res)
+
+	res = nil
+	dto, err = GetData(uncountedGrid{}, GridDto{Paging: Paging{Page: 2, Size: 1}}, MariaDB, &res)
+	require.Nil(t, err)
+
+	assert.False(t, dto.Paging.HasMore)
+	assert.Equal(t, []uncountedGrid{{Id: 2}}, res)
+}
+
+type cappedGrid struct {
+	Id int `db:"t.id" grid:"filter,sort"`
+}
+
+func (T cappedGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("tag as t")
+}
+
+func (T cappedGrid) CountStrategy() CountStrategy {
+	return CappedCount{Limit: 1}
+}
+
+type estimatedGrid struct {
+	Id int `db:"t.id" grid:"filter,sort"`
+}
+
+func (T estimatedGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("tag as t")
+}
+
+func (T estimatedGrid) CountStrategy() CountStrategy {
+	return TableEstimate{Table: "tag"}
+}
+
+func Test_EstimateQuery(t *testing.T) {
+	assert.Equal(t, ExactCount{}, countStrategy(uncountedGrid{}))
+	assert.Equal(t, CappedCount{Limit: 1}, countStrategy(cappedGrid{}))
+
+	sql, args := estimateQuery(PostgreSQL, "public.orders")
+	assert.Equal(t, "SELECT reltuples::bigint FROM pg_class WHERE oid = to_regclass($1)", sql)
+	assert.Equal(t, []interface{}{"public.orders"}, args)
+
+	sql, args = estimateQuery(MySQL, "shop.orders")
+	assert.Equal(t, "SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?", sql)
+	assert.Equal(t, []interface{}{"shop", "orders"}, args)
+}
+
+func Test_CountStrategyGrid(t *testing.T) {
+	prepareTestData(t)
+
+	var res []cappedGrid
+	dto, err := GetData(cappedGrid{}, GridDto{}, MariaDB, &res)
+	require.Nil(t, err)
+
+	assert.Equal(t, 1, dto.Paging.Total)
+	assert.True(t, dto.Paging.Estimated)
+
+	dto, err = GetData(cappedGrid{}, GridDto{Filter: [][]Filter{{{Column: "id", Operator: Eq, Value: []string{"1"}}}}}, MariaDB, &res)
+	require.Nil(t, err)
+
+	assert.Equal(t, 1, dto.Paging.Total)
+	assert.False(t, dto.Paging.Estimated)
+
+	var estimated []estimatedGrid
+	dto, err = GetData(estimatedGrid{}, GridDto{}, MariaDB, &estimated)
+	require.Nil(t, err)
+
+	assert.True(t, dto.Paging.Estimated)
+
+	dto, err = GetData(estimatedGrid{}, GridDto{Filter: [][]Filter{{{Column: "id", Operator: Gte, Value: []string{"1"}}}}}, MariaDB, &estimated)
+	require.Nil(t, err)
+
+	assert.Equal(t, 2, dto.Paging.Total)
+	assert.False(t, dto.Paging.Estimated)
+}
+
+func Test_CountCacheGrid(t *testing.T) {
+	prepareTestData(t)
+
+	Counts = NewMemoryCountCache(10, time.Minute)
+	t.Cleanup(func() {
+		Counts = nil
+	})
+
+	var res []estimatedGrid
+	dto, err := GetData(estimatedGrid{}, GridDto{Filter: [][]Filter{{{Column: "id", Operator: Gte, Value: []string{"1"}}}}}, MariaDB, &res)
+	require.Nil(t, err)
+	assert.Equal(t, 2, dto.Paging.Total)
+
+	_, err = MariaDB.Exec("INSERT INTO tag VALUES (3, 2, 'Kapr')")
+	require.Nil(t, err)
+
+	dto, err = GetData(estimatedGrid{}, GridDto{Filter: [][]Filter{{{Column: "id", Operator: Gte, Value: []string{"1"}}}}, Paging: Paging{Page: 2}}, MariaDB, &res)
+	require.Nil(t, err)
+	assert.Equal(t, 2, dto.Paging.Total)
+
+	InvalidateCounts(estimatedGrid{})
+	dto, err = GetData(estimatedGrid{}, GridDto{Filter: [][]Filter{{{Column: "id", Operator: Gte, Value: []string{"1"}}}}}, MariaDB, &res)
+	require.Nil(t, err)
+	assert.Equal(t, 3, dto.Paging.Total)
+}
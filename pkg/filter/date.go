@@ -0,0 +1,289 @@
+package filter
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	Last      = "LAST"
+	Today     = "TODAY"
+	ThisWeek  = "THIS_WEEK"
+	ThisMonth = "THIS_MONTH"
+	ThisYear  = "THIS_YEAR"
+
+	dateTimeFormat = "2006-01-02 15:04:05.999999"
+	dateFormat     = "2006-01-02"
+)
+
+// Clock resolving relative dates, replace to get fixed time in tests
+var Clock = time.Now
+
+// Location relative dates and datetime values are resolved in unless request defines its timezone
+var Location = time.Local
+
+// Location of datetime values stored in database
+var DatabaseLocation = time.Local
+
+var dateTimeFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+var timeTypes = []reflect.Type{
+	reflect.TypeOf(time.Time{}),
+	reflect.TypeOf(sql.NullTime{}),
+}
+
+var lastPattern = regexp.MustCompile(`^(\d+)([hdwmy])$`)
+
+type dateRange struct {
+	from time.Time
+	to   time.Time
+}
+
+// Replaces relative operators (LAST, THIS_MONTH, ...) and values (@today, ...) with concrete bounds
+// Values of time fields are read in given location, dates cover the whole day
+func resolveDates(model Grid, filter Filter, location *time.Location) (Filter, error) {
+	now := Clock().In(location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	timeField := isTimeField(model, filter.Column)
+
+	switch filter.Operator {
+	case Last, Today, ThisWeek, ThisMonth, ThisYear:
+		if !timeField {
+			return filter, fmt.Errorf("field [%s] is not a time field", filter.Column)
+		}
+	}
+
+	var period *dateRange
+	switch filter.Operator {
+	case Last:
+		if len(filter.Value) != 1 {
+			return filter, fmt.Errorf("operator [%s] of field [%s] requires single value", Last, filter.Column)
+		}
+
+		matches := lastPattern.FindStringSubmatch(filter.Value[0])
+		if matches == nil {
+			return filter, fmt.Errorf("invalid relative date [%s] for field [%s]", filter.Value[0], filter.Column)
+		}
+
+		n, _ := strconv.Atoi(matches[1])
+		from := now
+		switch matches[2] {
+		case "h":
+			from = now.Add(-time.Duration(n) * time.Hour)
+		case "d":
+			from = now.AddDate(0, 0, -n)
+		case "w":
+			from = now.AddDate(0, 0, -7*n)
+		case "m":
+			from = now.AddDate(0, -n, 0)
+		case "y":
+			from = now.AddDate(-n, 0, 0)
+		}
+		period = &dateRange{from: from, to: now}
+	case Today:
+		period = &dateRange{from: today, to: today.AddDate(0, 0, 1)}
+	case ThisWeek:
+		// Weeks start on Monday
+		monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+		period = &dateRange{from: monday, to: monday.AddDate(0, 0, 7)}
+	case ThisMonth:
+		month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location)
+		period = &dateRange{from: month, to: month.AddDate(0, 1, 0)}
+	case ThisYear:
+		year := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, location)
+		period = &dateRange{from: year, to: year.AddDate(1, 0, 0)}
+	}
+
+	if period != nil {
+		if filter.Operator != Last {
+			period.to = period.to.Add(-time.Microsecond)
+		}
+
+		return Filter{
+			Column:   filter.Column,
+			Operator: Between,
+			Value:    []string{formatDate(period.from), formatDate(period.to)},
+		}, nil
+	}
+
+	ranged := rangeOperator(filter.Operator)
+	ranges := make([]*dateRange, len(filter.Value))
+	resolved := false
+	for i, value := range filter.Value {
+		if relativeValue(value) {
+			if !timeField {
+				return filter, fmt.Errorf("field [%s] is not a time field", filter.Column)
+			}
+			if !ranged {
+				return filter, fmt.Errorf("operator [%s] of field [%s] does not support relative dates", filter.Operator, filter.Column)
+			}
+		}
+
+		var day time.Time
+		switch value {
+		case "@now":
+			ranges[i] = &dateRange{from: now, to: now}
+			resolved = true
+
+			continue
+		case "@today":
+			day = today
+		case "@yesterday":
+			day = today.AddDate(0, 0, -1)
+		case "@tomorrow":
+			day = today.AddDate(0, 0, 1)
+		default:
+			// Patterns (STARTS=2024-03) and lists are compared as they are
+			if !timeField || !ranged {
+				continue
+			}
+
+			if date, err := time.ParseInLocation(dateFormat, value, location); err == nil {
+				day = date
+			} else if date, ok := parseDateTime(value, location); ok {
+				ranges[i] = &dateRange{from: date, to: date}
+				resolved = true
+
+				continue
+			} else {
+				continue
+			}
+		}
+
+		ranges[i] = &dateRange{from: day, to: day.AddDate(0, 0, 1).Add(-time.Microsecond)}
+		resolved = true
+	}
+
+	if !resolved {
+		return filter, nil
+	}
+
+	return expandRanges(filter, ranges), nil
+}
+
+// Operators comparing against bounds, dates and datetimes of other operators are kept as sent
+func rangeOperator(operator string) bool {
+	switch operator {
+	case Eq, Neq, Gt, Gte, Lt, Lte, Between, Nbetween:
+		return true
+	}
+
+	return false
+}
+
+func relativeValue(value string) bool {
+	switch value {
+	case "@now", "@today", "@yesterday", "@tomorrow":
+		return true
+	}
+
+	return false
+}
+
+// Compares against whole ranges, nil range keeps its original value
+func expandRanges(filter Filter, ranges []*dateRange) Filter {
+	from := func(i int) string {
+		if ranges[i] == nil {
+			return filter.Value[i]
+		}
+
+		return formatDate(ranges[i].from)
+	}
+	to := func(i int) string {
+		if ranges[i] == nil {
+			return filter.Value[i]
+		}
+
+		return formatDate(ranges[i].to)
+	}
+
+	resolved := Filter{Column: filter.Column, Operator: filter.Operator, Value: make([]string, len(filter.Value))}
+	switch filter.Operator {
+	case Eq, Neq:
+		if len(filter.Value) == 1 && ranges[0] != nil && !ranges[0].from.Equal(ranges[0].to) {
+			resolved.Operator = Between
+			if filter.Operator == Neq {
+				resolved.Operator = Nbetween
+			}
+			resolved.Value = []string{from(0), to(0)}
+
+			return resolved
+		}
+	case Gt, Lte:
+		if len(filter.Value) == 1 {
+			resolved.Value[0] = to(0)
+
+			return resolved
+		}
+	case Between, Nbetween:
+		if len(filter.Value) == 2 {
+			resolved.Value = []string{from(0), to(1)}
+
+			return resolved
+		}
+	}
+
+	for i := range filter.Value {
+		resolved.Value[i] = from(i)
+	}
+
+	return resolved
+}
+
+func formatDate(date time.Time) string {
+	return date.In(DatabaseLocation).Format(dateTimeFormat)
+}
+
+func parseDateTime(value string, location *time.Location) (time.Time, bool) {
+	for _, format := range dateTimeFormats {
+		if date, err := time.ParseInLocation(format, value, location); err == nil {
+			return date, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func isTimeField(model Grid, column string) bool {
+	field, ok := gridField(model, column)
+	if !ok {
+		return false
+	}
+
+	fType := field.Type
+	if fType.Kind() == reflect.Ptr {
+		fType = fType.Elem()
+	}
+
+	for _, timeType := range timeTypes {
+		if fType == timeType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Location of request timezone, Location when not given
+func requestLocation(dto GridDto) (*time.Location, error) {
+	if dto.Timezone == "" {
+		return Location, nil
+	}
+
+	location, err := time.LoadLocation(dto.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone [%s]", dto.Timezone)
+	}
+
+	return location, nil
+}
@@ -0,0 +1,158 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dateGrid struct {
+	Id        int        `db:"e.id" grid:"filter"`
+	Name      string     `db:"e.name" grid:"filter"`
+	CreatedAt time.Time  `db:"e.created_at" grid:"filter"`
+	DeletedAt *time.Time `db:"e.deleted_at" grid:"filter"`
+}
+
+func (T dateGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("entity e")
+}
+
+func withClock(t *testing.T, now time.Time) {
+	clock, location, dbLocation := Clock, Location, DatabaseLocation
+	Clock = func() time.Time { return now }
+	Location = now.Location()
+	DatabaseLocation = now.Location()
+
+	t.Cleanup(func() {
+		Clock, Location, DatabaseLocation = clock, location, dbLocation
+	})
+}
+
+func Test_ResolveDates(t *testing.T) {
+	withClock(t, time.Date(2024, 3, 14, 15, 30, 0, 0, time.UTC))
+
+	tests := []struct {
+		filter Filter
+		exp    Filter
+	}{
+		{
+			Filter{Column: "createdAt", Operator: Last, Value: []string{"7d"}},
+			Filter{Column: "createdAt", Operator: Between, Value: []string{"2024-03-07 15:30:00", "2024-03-14 15:30:00"}},
+		},
+		{
+			Filter{Column: "createdAt", Operator: ThisMonth, Value: []string{"1"}},
+			Filter{Column: "createdAt", Operator: Between, Value: []string{"2024-03-01 00:00:00", "2024-03-31 23:59:59.999999"}},
+		},
+		{
+			Filter{Column: "createdAt", Operator: ThisWeek, Value: []string{"1"}},
+			Filter{Column: "createdAt", Operator: Between, Value: []string{"2024-03-11 00:00:00", "2024-03-17 23:59:59.999999"}},
+		},
+		{
+			Filter{Column: "createdAt", Operator: Eq, Value: []string{"@today"}},
+			Filter{Column: "createdAt", Operator: Between, Value: []string{"2024-03-14 00:00:00", "2024-03-14 23:59:59.999999"}},
+		},
+		{
+			Filter{Column: "createdAt", Operator: Neq, Value: []string{"@yesterday"}},
+			Filter{Column: "createdAt", Operator: Nbetween, Value: []string{"2024-03-13 00:00:00", "2024-03-13 23:59:59.999999"}},
+		},
+		{
+			Filter{Column: "createdAt", Operator: Gt, Value: []string{"@today"}},
+			Filter{Column: "createdAt", Operator: Gt, Value: []string{"2024-03-14 23:59:59.999999"}},
+		},
+		{
+			Filter{Column: "createdAt", Operator: Between, Value: []string{"2024-01-01", "@yesterday"}},
+			Filter{Column: "createdAt", Operator: Between, Value: []string{"2024-01-01 00:00:00", "2024-03-13 23:59:59.999999"}},
+		},
+		{
+			Filter{Column: "createdAt", Operator: Lt, Value: []string{"@now"}},
+			Filter{Column: "createdAt", Operator: Lt, Value: []string{"2024-03-14 15:30:00"}},
+		},
+		{
+			Filter{Column: "name", Operator: Eq, Value: []string{"today"}},
+			Filter{Column: "name", Operator: Eq, Value: []string{"today"}},
+		},
+	}
+
+	for _, test := range tests {
+		resolved, err := resolveDates(dateGrid{}, test.filter, Location)
+		require.Nil(t, err)
+		assert.Equal(t, test.exp, resolved)
+	}
+
+	_, err := resolveDates(dateGrid{}, Filter{Column: "createdAt", Operator: Last, Value: []string{"week"}}, Location)
+	assert.EqualError(t, err, "invalid relative date [week] for field [createdAt]")
+
+	for _, filter := range []Filter{
+		{Column: "name", Operator: Last, Value: []string{"7d"}},
+		{Column: "name", Operator: Today, Value: []string{"1"}},
+		{Column: "name", Operator: ThisYear, Value: []string{"1"}},
+		{Column: "name", Operator: Eq, Value: []string{"@today"}},
+		{Column: "name", Operator: Between, Value: []string{"a", "@now"}},
+	} {
+		_, err = resolveDates(dateGrid{}, filter, Location)
+		assert.EqualError(t, err, "field [name] is not a time field")
+	}
+}
+
+func Test_ResolveDatesInTimezone(t *testing.T) {
+	withClock(t, time.Date(2024, 3, 14, 23, 30, 0, 0, time.UTC))
+
+	prague, err := time.LoadLocation("Europe/Prague")
+	require.Nil(t, err)
+
+	tests := []struct {
+		filter Filter
+		exp    Filter
+	}{
+		{
+			Filter{Column: "createdAt", Operator: Gte, Value: []string{"2024-03-01"}},
+			Filter{Column: "createdAt", Operator: Gte, Value: []string{"2024-02-29 23:00:00"}},
+		},
+		{
+			Filter{Column: "deletedAt", Operator: Eq, Value: []string{"2024-03-01"}},
+			Filter{Column: "deletedAt", Operator: Between, Value: []string{"2024-02-29 23:00:00", "2024-03-01 22:59:59.999999"}},
+		},
+		{
+			Filter{Column: "createdAt", Operator: Lt, Value: []string{"2024-03-01 08:15"}},
+			Filter{Column: "createdAt", Operator: Lt, Value: []string{"2024-03-01 07:15:00"}},
+		},
+		{
+			Filter{Column: "createdAt", Operator: Lte, Value: []string{"2024-07-01T08:15:00+02:00"}},
+			Filter{Column: "createdAt", Operator: Lte, Value: []string{"2024-07-01 06:15:00"}},
+		},
+		{
+			Filter{Column: "createdAt", Operator: Eq, Value: []string{"@today"}},
+			Filter{Column: "createdAt", Operator: Between, Value: []string{"2024-03-14 23:00:00", "2024-03-15 22:59:59.999999"}},
+		},
+		{
+			Filter{Column: "name", Operator: Eq, Value: []string{"2024-03-01"}},
+			Filter{Column: "name", Operator: Eq, Value: []string{"2024-03-01"}},
+		},
+	}
+
+	for _, test := range tests {
+		resolved, err := resolveDates(dateGrid{}, test.filter, prague)
+		require.Nil(t, err)
+		assert.Equal(t, test.exp, resolved)
+	}
+
+	for _, filter := range []Filter{
+		{Column: "createdAt", Operator: Starts, Value: []string{"2024-03"}},
+		{Column: "createdAt", Operator: Starts, Value: []string{"2024-03-01"}},
+		{Column: "createdAt", Operator: Like, Value: []string{"2024-03-01 08:15"}},
+		{Column: "createdAt", Operator: In, Value: []string{"2024-03-01", "2024-03-02"}},
+	} {
+		resolved, err := resolveDates(dateGrid{}, filter, prague)
+		require.Nil(t, err)
+		assert.Equal(t, filter, resolved)
+	}
+
+	_, err = resolveDates(dateGrid{}, Filter{Column: "createdAt", Operator: In, Value: []string{"@today"}}, prague)
+	assert.EqualError(t, err, "operator [IN] of field [createdAt] does not support relative dates")
+
+	_, err = requestLocation(GridDto{Timezone: "Mars/Olympus"})
+	assert.EqualError(t, err, "invalid timezone [Mars/Olympus]")
+}
@@ -0,0 +1,112 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type Dialect string
+
+const (
+	MySQL      Dialect = "mysql"
+	PostgreSQL Dialect = "postgres"
+)
+
+func dialectOf(db Executor) Dialect {
+	switch db.DriverName() {
+	case "postgres", "pgx", "pq":
+		return PostgreSQL
+	}
+
+	return MySQL
+}
+
+func (d Dialect) FormQuery(field, operator string, values []string, safe bool) squirrel.Sqlizer {
+	return squirrel.Expr(
+		d.OperatorToQuery(operator, field, len(values), safe),
+		ParseValues(values, operator)...,
+	)
+}
+
+func (d Dialect) OperatorToQuery(operator, column string, values int, safe bool) string {
+	switch operator {
+	case Ieq:
+		return fmt.Sprintf("LOWER(%s) = LOWER(?)", d.Name(column, safe))
+	case Ilike, Istarts, Iends:
+		if d == PostgreSQL {
+			return fmt.Sprintf("%s ILIKE ?", d.Name(column, safe))
+		}
+
+		return fmt.Sprintf("LOWER(%s) LIKE LOWER(?)", d.Name(column, safe))
+	case Regex:
+		if d == PostgreSQL {
+			return fmt.Sprintf("%s ~ ?", d.Name(column, safe))
+		}
+
+		return fmt.Sprintf("%s REGEXP ?", d.Name(column, safe))
+	case Nregex:
+		if d == PostgreSQL {
+			return fmt.Sprintf("%s !~ ?", d.Name(column, safe))
+		}
+
+		return fmt.Sprintf("%s NOT REGEXP ?", d.Name(column, safe))
+	}
+
+	return operatorToQuery(operator, d.Name(column, safe), values)
+}
+
+// Collated compares column within given collation instead of lowering both sides (MySQL only)
+func (d Dialect) Collated(field, collation, operator string, values []string, safe bool) squirrel.Sqlizer {
+	if d != MySQL || collation == "" || !caseInsensitive(operator) {
+		return d.FormQuery(field, operator, values, safe)
+	}
+
+	return squirrel.Expr(
+		operatorToQuery(
+			strings.TrimPrefix(operator, "I"),
+			fmt.Sprintf("%s COLLATE %s", d.Name(field, safe), collation),
+			len(values),
+		),
+		ParseValues(values, operator)...,
+	)
+}
+
+func (d Dialect) Name(name string, safe bool) string {
+	if safe {
+		cols := strings.Split(name, ".")
+		for i, col := range cols {
+			cols[i] = d.quote(col)
+		}
+
+		return strings.Join(cols, ".")
+	}
+
+	return name
+}
+
+func (d Dialect) quote(name string) string {
+	if d == PostgreSQL {
+		return fmt.Sprintf(`"%s"`, name)
+	}
+
+	return fmt.Sprintf("`%s`", name)
+}
+
+func (d Dialect) placeholder() squirrel.PlaceholderFormat {
+	if d == PostgreSQL {
+		return squirrel.Dollar
+	}
+
+	return squirrel.Question
+}
+
+func caseInsensitive(operator string) bool {
+	switch operator {
+	case Ieq, Ilike, Istarts, Iends:
+		return true
+	}
+
+	return false
+}
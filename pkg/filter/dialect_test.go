@@ -0,0 +1,66 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CaseInsensitiveOperators(t *testing.T) {
+	assert.Equal(t, "LOWER(`f`.`name`) = LOWER(?)", MySQL.OperatorToQuery(Ieq, "f.name", 1, true))
+	assert.Equal(t, "LOWER(`f`.`name`) LIKE LOWER(?)", MySQL.OperatorToQuery(Ilike, "f.name", 1, true))
+	assert.Equal(t, `LOWER("f"."name") = LOWER(?)`, PostgreSQL.OperatorToQuery(Ieq, "f.name", 1, true))
+	assert.Equal(t, `"f"."name" ILIKE ?`, PostgreSQL.OperatorToQuery(Istarts, "f.name", 1, true))
+	assert.Equal(t, "`f`.`name` = ?", OperatorToQuery(Eq, "f.name", 1, true))
+
+	sql, args, err := MySQL.Collated("f.name", "utf8mb4_unicode_ci", Istarts, []string{"Žlu"}, true).ToSql()
+	require.Nil(t, err)
+	assert.Equal(t, "`f`.`name` COLLATE utf8mb4_unicode_ci LIKE ?", sql)
+	assert.Equal(t, []interface{}{"Žlu%"}, args)
+
+	sql, _, err = MySQL.Collated("f.name", "utf8mb4_unicode_ci", Eq, []string{"Žlu"}, true).ToSql()
+	require.Nil(t, err)
+	assert.Equal(t, "`f`.`name` = ?", sql)
+
+	sql, args, err = PostgreSQL.Collated("f.name", "utf8mb4_unicode_ci", Iends, []string{"ová"}, true).ToSql()
+	require.Nil(t, err)
+	assert.Equal(t, `"f"."name" ILIKE ?`, sql)
+	assert.Equal(t, []interface{}{"%ová"}, args)
+}
+
+type regexGrid struct {
+	Id   int    `db:"p.id" grid:"filter"`
+	Code string `db:"p.code" grid:"filter=EQ|REGEX|NREGEX"`
+}
+
+func (T regexGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("product p")
+}
+
+func Test_RegexOperators(t *testing.T) {
+	assert.Equal(t, "`p`.`code` REGEXP ?", MySQL.OperatorToQuery(Regex, "p.code", 1, true))
+	assert.Equal(t, "`p`.`code` NOT REGEXP ?", MySQL.OperatorToQuery(Nregex, "p.code", 1, true))
+	assert.Equal(t, `"p"."code" ~ ?`, PostgreSQL.OperatorToQuery(Regex, "p.code", 1, true))
+	assert.Equal(t, `"p"."code" !~ ?`, PostgreSQL.OperatorToQuery(Nregex, "p.code", 1, true))
+
+	assert.Nil(t, checkOperator(regexGrid{}, Filter{Column: "code", Operator: Regex, Value: []string{`^[A-Z]{3}-\d+$`}}))
+	assert.Nil(t, checkOperator(regexGrid{}, Filter{Column: "id", Operator: Gte, Value: []string{"1"}}))
+	assert.EqualError(
+		t,
+		checkOperator(regexGrid{}, Filter{Column: "code", Operator: Like, Value: []string{"A"}}),
+		"operator [LIKE] is not allowed for field [code]",
+	)
+	assert.EqualError(
+		t,
+		checkOperator(regexGrid{}, Filter{Column: "id", Operator: Regex, Value: []string{"1"}}),
+		"operator [REGEX] is not allowed for field [id]",
+	)
+	assert.EqualError(
+		t,
+		checkOperator(regexGrid{}, Filter{Column: "code", Operator: Nregex, Value: []string{strings.Repeat("a", RegexMaxLength+1)}}),
+		"pattern for field [code] exceeds 255 characters",
+	)
+}
@@ -0,0 +1,205 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+const facetable = "facet"
+
+// Most frequent values returned per facet
+var FacetLimit = 50
+
+type Facet struct {
+	Column string       `json:"column"`
+	Values []FacetValue `json:"values"`
+}
+
+type FacetValue struct {
+	Value *string `db:"value" json:"value"`
+	Count int     `db:"count" json:"count"`
+}
+
+// Counts of facet values within current filters and search, filter groups of the facet itself are left out
+func facets(ctx context.Context, d Dialect, model Grid, db Executor, dto GridDto, location *time.Location, search squirrel.Sqlizer) ([]Facet, error) {
+	for _, column := range dto.Facet {
+		if err := checkFacet(model, column); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]Facet, 0, len(dto.Facet))
+	for _, column := range dto.Facet {
+		where, callbacks, err := filterQueries(d, model, otherGroups(model, dto.Filter, column), location)
+		if err != nil {
+			return nil, err
+		}
+		if search != nil {
+			where = append(where, search)
+		}
+
+		qb, err := facetQuery(d, model, column, where, callbacks)
+		if err != nil {
+			return nil, err
+		}
+
+		sql, args, err := qb.OrderBy(fmt.Sprintf("%s %s", d.quote("count"), Desc), fmt.Sprintf("%s %s", d.quote("value"), Asc)).
+			Limit(uint64(FacetLimit)).
+			ToSql()
+		if err != nil {
+			return nil, err
+		}
+
+		values := make([]FacetValue, 0)
+		if err = sqlx.SelectContext(ctx, db, &values, sql, args...); err != nil {
+			return nil, err
+		}
+
+		result = append(result, Facet{Column: column, Values: values})
+	}
+
+	return result, nil
+}
+
+func checkFacet(model Grid, column string) error {
+	if err := checkSelectable(model, column); err != nil {
+		return err
+	}
+	if !hasTag(model, column, facetable) {
+		return fmt.Errorf("field [%s] is not tagged as facet", column)
+	}
+
+	return nil
+}
+
+// Filter groups not filtering given column
+func otherGroups(model Grid, groups [][]Filter, column string) [][]Filter {
+	var others [][]Filter
+	for _, filters := range groups {
+		own := false
+		for _, filter := range filters {
+			if taggedName(model, filter.Column) == taggedName(model, column) {
+				own = true
+				break
+			}
+		}
+
+		if !own {
+			others = append(others, filters)
+		}
+	}
+
+	return others
+}
+
+// Values of column counted within filtered query
+func facetQuery(d Dialect, model Grid, column string, where squirrel.And, callbacks callbackStack) (squirrel.SelectBuilder, error) {
+	expression, safe, err := columnExpression(d, model, column)
+	if err != nil {
+		return squirrel.SelectBuilder{}, err
+	}
+
+	value := d.quote("value")
+	count := d.quote("count")
+	inner := model.SearchQuery(squirrel.Select(fmt.Sprintf("%s AS %s", d.Name(expression, safe), value))).Where(where)
+
+	return squirrel.Select(value, fmt.Sprintf("COUNT(*) AS %s", count)).
+		PlaceholderFormat(d.placeholder()).
+		FromSelect(callbacks.merge(inner), "facet").
+		GroupBy(value), nil
+}
+
+// Distinct values of filterable column (up to limit, FacetLimit when not positive) starting with prefix
+// within current search and filters except those of the column itself, sorted by value
+func GetDistinct(model Grid, dto GridDto, db Executor, column, prefix string, limit int) ([]FacetValue, error) {
+	return GetDistinctContext(context.Background(), model, dto, db, column, prefix, limit)
+}
+
+func GetDistinctContext(ctx context.Context, model Grid, dto GridDto, db Executor, column, prefix string, limit int) ([]FacetValue, error) {
+	if err := checkDistinct(model, column); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = FacetLimit
+	}
+
+	d := dialectOf(db)
+	location, err := requestLocation(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	qb, err := distinctQuery(d, model, dto, column, prefix, location)
+	if err != nil {
+		return nil, err
+	}
+
+	sql, args, err := qb.OrderBy(fmt.Sprintf("%s %s", d.quote("value"), Asc)).Limit(uint64(limit)).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]FacetValue, 0)
+	if err = sqlx.SelectContext(ctx, db, &values, sql, args...); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// Skipped fields are not selectable unless dynamic key of JSON column is requested
+func checkDistinct(model Grid, column string) error {
+	if err := checkSelectable(model, column); err != nil {
+		return err
+	}
+	if !hasTag(model, column, filterable) {
+		return fmt.Errorf("field [%s] is not tagged for filtering", column)
+	}
+
+	if hasTag(model, column, skip) {
+		if path, err := jsonPathOf(model, column); err != nil || path == "" {
+			return fmt.Errorf("field [%s] is not selectable", column)
+		}
+	}
+
+	return nil
+}
+
+// Prefix of aggregated field is filtered within HAVING
+func distinctQuery(d Dialect, model Grid, dto GridDto, column, prefix string, location *time.Location) (squirrel.SelectBuilder, error) {
+	where, callbacks, err := filterQueries(d, model, otherGroups(model, dto.Filter, column), location)
+	if err != nil {
+		return squirrel.SelectBuilder{}, err
+	}
+
+	search, err := searchQuery(d, model, tokenize(dto.Search))
+	if err != nil {
+		return squirrel.SelectBuilder{}, err
+	}
+	if search != nil {
+		where = append(where, search)
+	}
+
+	if prefix != "" {
+		query, err := filterQuery(d, model, Filter{Column: column, Operator: Istarts, Value: []string{prefix}})
+		if err != nil {
+			return squirrel.SelectBuilder{}, err
+		}
+
+		if _, ok := tagValue(model, column, aggregate); ok {
+			callbacks = append(callbacks, callbackStackItem{
+				callback: func(qb squirrel.SelectBuilder, _, _ string, _ []string) squirrel.SelectBuilder {
+					return qb.Having(query)
+				},
+			})
+		} else {
+			where = append(where, query)
+		}
+	}
+
+	return facetQuery(d, model, column, where, callbacks)
+}
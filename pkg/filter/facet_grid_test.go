@@ -0,0 +1,148 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type facetGrid struct {
+	Id     int    `db:"t.id" grid:"filter,sort"`
+	FileId int    `db:"t.file_id" grid:"filter,facet"`
+	Name   string `db:"t.Name" grid:"filter,facet"`
+}
+
+func (T facetGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("tag as t")
+}
+
+func Test_FacetQuery(t *testing.T) {
+	groups := [][]Filter{
+		{{Column: "name", Operator: Eq, Value: []string{"Losos"}}},
+		{{Column: "id", Operator: Gt, Value: []string{"0"}}, {Column: "fileId", Operator: Eq, Value: []string{"1"}}},
+	}
+	assert.Equal(t, groups[1:], otherGroups(facetGrid{}, groups, "name"))
+	assert.Equal(t, groups[:1], otherGroups(facetGrid{}, groups, "fileId"))
+
+	where, callbacks, err := filterQueries(PostgreSQL, facetGrid{}, groups[1:], Location)
+	require.Nil(t, err)
+
+	qb, err := facetQuery(PostgreSQL, facetGrid{}, "name", where, callbacks)
+	require.Nil(t, err)
+
+	sql, args, err := qb.ToSql()
+	require.Nil(t, err)
+	assert.Equal(
+		t,
+		`SELECT "value", COUNT(*) AS "count" FROM (SELECT "t"."Name" AS "value" FROM tag as t `+
+			`WHERE (("t"."id" > $1 OR "t"."file_id" = $2))) AS facet GROUP BY "value"`,
+		sql,
+	)
+	assert.Equal(t, []interface{}{"0", "1"}, args)
+}
+
+func Test_DistinctQuery(t *testing.T) {
+	qb, err := distinctQuery(MySQL, aggregateGrid{}, GridDto{}, "tagCount", "1", Location)
+	require.Nil(t, err)
+
+	sql, args, err := qb.ToSql()
+	require.Nil(t, err)
+	assert.Equal(
+		t,
+		"SELECT `value`, COUNT(*) AS `count` FROM (SELECT COUNT(t.id) AS `value` FROM file as f "+
+			"LEFT JOIN tag as t ON f.id = t.file_id WHERE (1=1) GROUP BY f.id HAVING LOWER(COUNT(t.id)) LIKE LOWER(?)) AS facet GROUP BY `value`",
+		sql,
+	)
+	assert.Equal(t, []interface{}{"1%"}, args)
+
+	assert.Nil(t, checkDistinct(jsonGrid{}, "attrs.color"))
+	assert.EqualError(t, checkDistinct(jsonGrid{}, "attrs"), "field [attrs] is not selectable")
+	assert.EqualError(t, checkDistinct(relationGrid{}, "tagName"), "field [tagName] is not selectable")
+	assert.EqualError(t, checkDistinct(facetGrid{}, "unknown"), "field [unknown] is not tagged for filtering")
+}
+
+func Test_FacetValidation(t *testing.T) {
+	// Unreachable database fails any query, invalid facet must fail before
+	db, err := sqlx.Open("mysql", "root@tcp(127.0.0.1:1)/none")
+	require.Nil(t, err)
+	defer db.Close()
+
+	var res []facetGrid
+	_, err = GetData(facetGrid{}, GridDto{Facet: []string{"id"}}, db, &res)
+	assert.EqualError(t, err, "field [id] is not tagged as facet")
+}
+
+func Test_FacetGrid(t *testing.T) {
+	prepareTestData(t)
+
+	dto := GridDto{
+		Filter: [][]Filter{
+			{
+				{
+					Column:   "name",
+					Operator: "EQ",
+					Value:    []string{"Losos"},
+				},
+			},
+		},
+		Facet: []string{"name", "fileId"},
+	}
+
+	var res []facetGrid
+	dto, err := GetData(facetGrid{}, dto, MariaDB, &res)
+	require.Nil(t, err)
+
+	losos, other, file := "Losos", "22", "1"
+	assert.Equal(t, 1, dto.Paging.Total)
+	assert.Equal(t, []Facet{
+		{Column: "name", Values: []FacetValue{{Value: &other, Count: 1}, {Value: &losos, Count: 1}}},
+		{Column: "fileId", Values: []FacetValue{{Value: &file, Count: 1}}},
+	}, dto.Facets)
+
+	dto.Facet = []string{"id"}
+	_, err = GetData(facetGrid{}, dto, MariaDB, &res)
+	assert.EqualError(t, err, "field [id] is not tagged as facet")
+}
+
+func Test_DistinctGrid(t *testing.T) {
+	prepareTestData(t)
+
+	dto := GridDto{
+		Filter: [][]Filter{
+			{
+				{
+					Column:   "name",
+					Operator: "EQ",
+					Value:    []string{"22"},
+				},
+			},
+			{
+				{
+					Column:   "id",
+					Operator: "GTE",
+					Value:    []string{"1"},
+				},
+			},
+		},
+	}
+
+	values, err := GetDistinct(facetGrid{}, dto, MariaDB, "name", "", 0)
+	require.Nil(t, err)
+
+	losos, other := "Losos", "22"
+	assert.Equal(t, []FacetValue{{Value: &other, Count: 1}, {Value: &losos, Count: 1}}, values)
+
+	values, err = GetDistinct(facetGrid{}, dto, MariaDB, "name", "lo", 10)
+	require.Nil(t, err)
+	assert.Equal(t, []FacetValue{{Value: &losos, Count: 1}}, values)
+
+	values, err = GetDistinct(facetGrid{}, dto, MariaDB, "fileId", "", 10)
+	require.Nil(t, err)
+	assert.Len(t, values, 1)
+
+	_, err = GetDistinct(facetGrid{}, dto, MariaDB, "unknown", "", 10)
+	assert.EqualError(t, err, "field [unknown] is not tagged for filtering")
+}
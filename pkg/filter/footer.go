@@ -0,0 +1,158 @@
+package filter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const (
+	Sum = "sum"
+	Avg = "avg"
+	Min = "min"
+	Max = "max"
+
+	footer = "footer"
+)
+
+var numericTypes = []reflect.Type{
+	reflect.TypeOf(sql.NullInt32{}),
+	reflect.TypeOf(sql.NullInt64{}),
+	reflect.TypeOf(sql.NullFloat64{}),
+}
+
+var footerFunctions = map[string]string{
+	Sum: "SUM",
+	Avg: "AVG",
+	Min: "MIN",
+	Max: "MAX",
+}
+
+// Values of aggregates keyed by column and function, NULL when there are no rows
+type AggregateValues map[string]map[string]*float64
+
+type Aggregate struct {
+	Column   string `json:"column"`
+	Function string `json:"function"`
+}
+
+// Field may whitelist functions (`footer=sum|avg`), all of them are allowed otherwise
+// Aggregates are read as numbers, so only numeric fields may be aggregated
+func checkAggregate(model Grid, aggregate Aggregate) error {
+	if err := checkSelectable(model, aggregate.Column); err != nil {
+		return err
+	}
+	if !hasTag(model, aggregate.Column, footer) {
+		return fmt.Errorf("field [%s] is not tagged for aggregation", aggregate.Column)
+	}
+	if !isNumericField(model, aggregate.Column) {
+		return fmt.Errorf("field [%s] is not numeric", aggregate.Column)
+	}
+	if aggregate.Function == "" {
+		return fmt.Errorf("aggregate of field [%s] requires function", aggregate.Column)
+	}
+
+	if _, ok := footerFunctions[aggregate.Function]; !ok {
+		return fmt.Errorf("aggregate [%s] is not allowed for field [%s]", aggregate.Function, aggregate.Column)
+	}
+
+	allowed, ok := tagValue(model, aggregate.Column, footer)
+	if !ok {
+		return nil
+	}
+
+	for _, function := range strings.Split(allowed, "|") {
+		if strings.ToLower(strings.TrimSpace(function)) == aggregate.Function {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("aggregate [%s] is not allowed for field [%s]", aggregate.Function, aggregate.Column)
+}
+
+func isNumericField(model Grid, column string) bool {
+	field, ok := gridField(model, column)
+	if !ok {
+		return false
+	}
+
+	fType := field.Type
+	if fType.Kind() == reflect.Ptr {
+		fType = fType.Elem()
+	}
+
+	for _, numericType := range numericTypes {
+		if fType == numericType {
+			return true
+		}
+	}
+
+	return fType.Kind() >= reflect.Int && fType.Kind() <= reflect.Uint64 || fType.Kind() == reflect.Float32 || fType.Kind() == reflect.Float64
+}
+
+// Aggregates of whole filtered result
+func footers(ctx context.Context, d Dialect, model Grid, db Executor, aggregates []Aggregate, where squirrel.And, callbacks callbackStack) (AggregateValues, error) {
+	qb, err := footerQuery(d, model, aggregates, where, callbacks)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := qb.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]sql.NullFloat64, len(aggregates))
+	dest := make([]interface{}, len(aggregates))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	if err = db.QueryRowxContext(ctx, query, args...).Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	return aggregateValues(aggregates, values), nil
+}
+
+func aggregateValues(aggregates []Aggregate, values []sql.NullFloat64) AggregateValues {
+	result := AggregateValues{}
+	for i, aggregate := range aggregates {
+		if _, ok := result[aggregate.Column]; !ok {
+			result[aggregate.Column] = map[string]*float64{}
+		}
+
+		result[aggregate.Column][aggregate.Function] = nil
+		if values[i].Valid {
+			value := values[i].Float64
+			result[aggregate.Column][aggregate.Function] = &value
+		}
+	}
+
+	return result
+}
+
+// Filtered query (HAVING included) is wrapped so aggregated and grouped fields are summed up per row
+func footerQuery(d Dialect, model Grid, aggregates []Aggregate, where squirrel.And, callbacks callbackStack) (squirrel.SelectBuilder, error) {
+	var columns, functions []string
+	for i, aggregate := range aggregates {
+		expression, safe, err := columnExpression(d, model, aggregate.Column)
+		if err != nil {
+			return squirrel.SelectBuilder{}, err
+		}
+
+		alias := d.quote(fmt.Sprintf("a%d", i))
+		columns = append(columns, fmt.Sprintf("%s AS %s", d.Name(expression, safe), alias))
+		functions = append(functions, fmt.Sprintf("%s(%s)", footerFunctions[aggregate.Function], alias))
+	}
+
+	inner := model.SearchQuery(squirrel.Select(columns...)).Where(where)
+
+	return squirrel.Select(functions...).
+		PlaceholderFormat(d.placeholder()).
+		FromSelect(callbacks.merge(inner), "footer"), nil
+}
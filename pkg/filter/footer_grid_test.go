@@ -0,0 +1,120 @@
+package filter
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type footerGrid struct {
+	Id     int    `db:"t.id" grid:"filter,sort,footer"`
+	FileId int    `db:"t.file_id" grid:"filter,footer=sum|max"`
+	Name   string `db:"t.Name" grid:"filter"`
+}
+
+func (T footerGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("tag as t")
+}
+
+type typedFooterGrid struct {
+	Amount    sql.NullFloat64 `db:"o.amount" grid:"footer"`
+	Price     *float64        `db:"o.price" grid:"footer"`
+	Note      string          `db:"o.note" grid:"footer"`
+	CreatedAt time.Time       `db:"o.created_at" grid:"footer=min|max"`
+}
+
+func (T typedFooterGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("orders o")
+}
+
+func Test_FooterQuery(t *testing.T) {
+	assert.Nil(t, checkAggregate(typedFooterGrid{}, Aggregate{Column: "amount", Function: Sum}))
+	assert.Nil(t, checkAggregate(typedFooterGrid{}, Aggregate{Column: "price", Function: Avg}))
+	assert.EqualError(t, checkAggregate(typedFooterGrid{}, Aggregate{Column: "note", Function: Max}), "field [note] is not numeric")
+	assert.EqualError(t, checkAggregate(typedFooterGrid{}, Aggregate{Column: "createdAt", Function: Min}), "field [createdAt] is not numeric")
+	assert.EqualError(t, checkAggregate(typedFooterGrid{}, Aggregate{Column: "amount"}), "aggregate of field [amount] requires function")
+
+	assert.Nil(t, checkAggregate(footerGrid{}, Aggregate{Column: "id", Function: Avg}))
+	assert.Nil(t, checkAggregate(footerGrid{}, Aggregate{Column: "fileId", Function: Max}))
+	assert.EqualError(
+		t,
+		checkAggregate(footerGrid{}, Aggregate{Column: "fileId", Function: Avg}),
+		"aggregate [avg] is not allowed for field [fileId]",
+	)
+	assert.EqualError(
+		t,
+		checkAggregate(footerGrid{}, Aggregate{Column: "id", Function: "count"}),
+		"aggregate [count] is not allowed for field [id]",
+	)
+	assert.EqualError(
+		t,
+		checkAggregate(footerGrid{}, Aggregate{Column: "name", Function: Sum}),
+		"field [name] is not tagged for aggregation",
+	)
+
+	where, callbacks, err := filterQueries(MySQL, aggregateFooterGrid{}, [][]Filter{
+		{{Column: "tagCount", Operator: Gte, Value: []string{"1"}}},
+	}, Location)
+	require.Nil(t, err)
+
+	qb, err := footerQuery(MySQL, aggregateFooterGrid{}, []Aggregate{{Column: "tagCount", Function: Sum}}, where, callbacks)
+	require.Nil(t, err)
+
+	sql, args, err := qb.ToSql()
+	require.Nil(t, err)
+	assert.Equal(
+		t,
+		"SELECT SUM(`a0`) FROM (SELECT COUNT(t.id) AS `a0` FROM file as f LEFT JOIN tag as t ON f.id = t.file_id "+
+			"WHERE (1=1) GROUP BY f.id HAVING (COUNT(t.id) >= ?)) AS footer",
+		sql,
+	)
+	assert.Equal(t, []interface{}{"1"}, args)
+}
+
+type aggregateFooterGrid struct {
+	Id       int `db:"f.id" grid:"filter,sort"`
+	TagCount int `db:"tagCount" grid:"filter,sort,footer,aggregate=COUNT(t.id)"`
+}
+
+func (T aggregateFooterGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return aggregateGrid{}.SearchQuery(qb)
+}
+
+func Test_FooterGrid(t *testing.T) {
+	prepareTestData(t)
+
+	dto := GridDto{
+		Filter: [][]Filter{},
+		Aggregate: []Aggregate{
+			{Column: "id", Function: Sum},
+			{Column: "id", Function: Avg},
+			{Column: "fileId", Function: Max},
+		},
+	}
+
+	var res []footerGrid
+	dto, err := GetData(footerGrid{}, dto, MariaDB, &res)
+	require.Nil(t, err)
+
+	sum, avg, max := 3.0, 1.5, 1.0
+	assert.Equal(t, AggregateValues{
+		"id":     {Sum: &sum, Avg: &avg},
+		"fileId": {Max: &max},
+	}, dto.Aggregates)
+
+	dto.Filter = [][]Filter{{{Column: "id", Operator: Gt, Value: []string{"2"}}}}
+	dto, err = GetData(footerGrid{}, dto, MariaDB, &res)
+	require.Nil(t, err)
+
+	assert.Nil(t, dto.Aggregates["id"][Sum])
+
+	var aggregated []aggregateFooterGrid
+	dto, err = GetData(aggregateFooterGrid{}, GridDto{Aggregate: []Aggregate{{Column: "tagCount", Function: Sum}}}, MariaDB, &aggregated)
+	require.Nil(t, err)
+
+	assert.Equal(t, 2.0, *dto.Aggregates["tagCount"][Sum])
+}
@@ -1,10 +1,13 @@
 package filter
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
@@ -17,6 +20,8 @@ const (
 	sortable   = "sort"
 	searchable = "search"
 	skip       = "skip"
+	collate    = "collate"
+	aggregate  = "aggregate"
 
 	Empty    = "EMPTY"
 	Nempty   = "NEMPTY"
@@ -34,8 +39,17 @@ const (
 	Nin      = "NIN"
 	Starts   = "STARTS"
 	Ends     = "ENDS"
+	Ieq      = "IEQ"
+	Ilike    = "ILIKE"
+	Istarts  = "ISTARTS"
+	Iends    = "IENDS"
+	Regex    = "REGEX"
+	Nregex   = "NREGEX"
 )
 
+// Longest pattern accepted by REGEX and NREGEX operators
+var RegexMaxLength = 255
+
 type FilterCallback func(field, operator string, values []string) squirrel.Sqlizer
 
 type QueryCallback func(qb squirrel.SelectBuilder, field, operator string, values []string) squirrel.SelectBuilder
@@ -63,7 +77,21 @@ type QueryCallbacks interface {
 	QueryCallbacks() map[string]QueryCallback
 }
 
-func GetData(model Grid, dto GridDto, db *sqlx.DB, resultSet interface{}) (GridDto, error) {
+func GetData(model Grid, dto GridDto, db Executor, resultSet interface{}) (GridDto, error) {
+	return GetDataContext(context.Background(), model, dto, db, resultSet)
+}
+
+func GetDataContext(ctx context.Context, model Grid, dto GridDto, db Executor, resultSet interface{}) (GridDto, error) {
+	err := inSnapshot(ctx, queryMode(model), db, func(db Executor) (err error) {
+		dto, err = getData(ctx, model, dto, db, resultSet)
+
+		return err
+	})
+
+	return dto, err
+}
+
+func getData(ctx context.Context, model Grid, dto GridDto, db Executor, resultSet interface{}) (GridDto, error) {
 	if dto.Paging.Size <= 0 {
 		dto.Paging.Size = defaultSize
 	}
@@ -71,59 +99,42 @@ func GetData(model Grid, dto GridDto, db *sqlx.DB, resultSet interface{}) (GridD
 		dto.Paging.Page = 1
 	}
 
-	filterCalls := map[string]FilterCallback{}
-	fcl, ok := interface{}(model).(FilterCallbacks)
-	if ok {
-		filterCalls = fcl.FilterCallbacks()
-	}
+	d := dialectOf(db)
 
-	queryCalls := map[string]QueryCallback{}
-	qcl, ok := interface{}(model).(QueryCallbacks)
-	if ok {
-		queryCalls = qcl.QueryCallbacks()
+	location, err := requestLocation(dto)
+	if err != nil {
+		return dto, err
 	}
 
-	callbacks := callbackStack{}
+	selected, err := projection(model, dto.Fields)
+	if err != nil {
+		return dto, err
+	}
 
-	// Filters
-	andQueries := squirrel.And{}
-	for _, filters := range dto.Filter {
-		var orQeuries squirrel.Or
-		for _, filter := range filters {
-			if hasTag(model, filter.Column, filterable) {
-				tagName := taggedName(model, filter.Column)
-				if callback, ok := filterCalls[tagName]; ok {
-					orQeuries = append(orQeuries, callback(tagName, filter.Operator, filter.Value))
-				} else if callback, ok := queryCalls[tagName]; ok {
-					callbacks = append(callbacks, callbackStackItem{
-						callback: callback,
-						field:    tagName,
-						operator: filter.Operator,
-						values:   filter.Value,
-					})
-				} else {
-					orQeuries = append(orQeuries, FormQuery(tagName, filter.Operator, filter.Value, true))
-				}
-			} else {
-				return dto, fmt.Errorf("field [%s] is not tagged for filtering", filter.Column)
-			}
+	for _, aggregate := range dto.Aggregate {
+		if err := checkAggregate(model, aggregate); err != nil {
+			return dto, err
 		}
-
-		if orQeuries != nil {
-			andQueries = append(andQueries, orQeuries)
+	}
+	for _, column := range dto.Facet {
+		if err := checkFacet(model, column); err != nil {
+			return dto, err
 		}
 	}
 
+	andQueries, callbacks, err := filterQueries(d, model, dto.Filter, location)
+	if err != nil {
+		return dto, err
+	}
+
 	// Search
-	if dto.Search != "" {
-		fields := getSearchFields(model)
-		var orQueries squirrel.Or
-		for _, field := range fields {
-			orQueries = append(orQueries, FormQuery(taggedName(model, field), Like, []string{dto.Search}, true))
-		}
-		if orQueries != nil {
-			andQueries = append(andQueries, orQueries)
-		}
+	tokens := tokenize(dto.Search)
+	query, err := searchQuery(d, model, tokens)
+	if err != nil {
+		return dto, err
+	}
+	if query != nil {
+		andQueries = append(andQueries, query)
 	}
 
 	sql, args, err := andQueries.ToSql()
@@ -131,7 +142,7 @@ func GetData(model Grid, dto GridDto, db *sqlx.DB, resultSet interface{}) (GridD
 		return dto, err
 	}
 
-	qb := model.SearchQuery(squirrel.Select("*")).Where(sql, args...)
+	qb := model.SearchQuery(squirrel.Select("*").PlaceholderFormat(d.placeholder())).Where(sql, args...)
 	sqlC, argsC, err := callbacks.merge(qb).ToSql()
 	if err != nil {
 		return dto, err
@@ -196,28 +207,37 @@ func GetData(model Grid, dto GridDto, db *sqlx.DB, resultSet interface{}) (GridD
 		sqlC = fmt.Sprintf("SELECT COUNT(*) FROM (%s) counter_alias;", sqlC)
 	}
 
-	var count []int
-	err = db.Select(&count, sqlC, argsC...)
-	if err != nil {
-		return dto, err
-	}
-
 	// Count query ends there
 
-	qb = createSelects(model, sqlInnerSelect).Where(sql, args...)
+	if len(dto.Aggregate) > 0 {
+		if dto.Aggregates, err = footers(ctx, d, model, db, dto.Aggregate, andQueries, callbacks); err != nil {
+			return dto, err
+		}
+	}
+
+	qb = createSelects(model, d, sqlInnerSelect, selected).Where(sql, args...)
 	qb = callbacks.merge(qb)
 
+	uncounted := skipCount(model, dto)
+	countQuery := CountQuery{
+		Dialect:  d,
+		Sql:      sqlC,
+		Args:     argsC,
+		Rows:     qb,
+		Filtered: len(andQueries) > 0 || len(callbacks) > 0,
+	}
+
 	// OrderBy
-	for _, sorter := range dto.Sorter {
-		if hasTag(model, sorter.Column, sortable) {
-			qb = qb.OrderBy(fmt.Sprintf("%s %s", Name(taggedName(model, sorter.Column), true), sorter.Direction))
-		} else {
-			return dto, fmt.Errorf("field [%s] is not tagged for sorting", sorter.Column)
-		}
+	if qb, err = orderBy(d, model, dto.Sorter, tokens, qb); err != nil {
+		return dto, err
 	}
 
 	// Paging
-	qb = qb.Limit(uint64(dto.Paging.Size)).
+	limit := dto.Paging.Size
+	if uncounted {
+		limit++
+	}
+	qb = qb.Limit(uint64(limit)).
 		Offset(uint64((dto.Paging.Page - 1) * dto.Paging.Size))
 
 	sql, args, err = qb.ToSql()
@@ -225,9 +245,54 @@ func GetData(model Grid, dto GridDto, db *sqlx.DB, resultSet interface{}) (GridD
 		return dto, err
 	}
 
-	c := count[0]
-	last := c / dto.Paging.Size
-	if last*dto.Paging.Size < c {
+	total, estimated := 0, false
+	counter := func(ctx context.Context, db Executor) (err error) {
+		if !uncounted {
+			total, estimated, err = cachedCount(ctx, model, dto, db, countQuery)
+		}
+
+		return err
+	}
+	loader := func(ctx context.Context, db Executor) error {
+		return sqlx.SelectContext(ctx, db, resultSet, sql, args...)
+	}
+
+	if err = runQueries(ctx, queryMode(model), db, counter, loader); err != nil {
+		return dto, err
+	}
+
+	if uncounted {
+		dto.Paging = uncountedPaging(dto.Paging, cutExtraRow(resultSet, dto.Paging.Size))
+	} else {
+		dto.Paging = paginate(dto.Paging, total)
+		dto.Paging.Estimated = estimated
+	}
+
+	if err = loadRelations(ctx, model, d, db, resultSet, selected); err != nil {
+		return dto, err
+	}
+
+	dto.Items = resultSet
+	if selected != nil {
+		dto.Items = projectItems(resultSet, selected)
+	}
+	if dto.Highlight {
+		dto.Highlights = highlightItems(model, resultSet, tokens)
+	}
+
+	if len(dto.Facet) > 0 {
+		if dto.Facets, err = facets(ctx, d, model, db, dto, location, query); err != nil {
+			return dto, err
+		}
+	}
+
+	return dto, nil
+}
+
+// Fills total, last, previous and next page of requested page
+func paginate(paging Paging, total int) Paging {
+	last := total / paging.Size
+	if last*paging.Size < total {
 		last++
 	}
 
@@ -235,106 +300,224 @@ func GetData(model Grid, dto GridDto, db *sqlx.DB, resultSet interface{}) (GridD
 		last = 1
 	}
 
-	dto.Paging.Total = c
-	dto.Paging.LastPage = last
+	paging.Total = total
+	paging.LastPage = last
+	paging.HasMore = paging.Page < last
 
-	dto.Paging.PreviousPage = dto.Paging.Page - 1
-	if dto.Paging.PreviousPage <= 0 {
-		dto.Paging.PreviousPage = 1
+	paging.PreviousPage = paging.Page - 1
+	if paging.PreviousPage <= 0 {
+		paging.PreviousPage = 1
 	}
 
-	dto.Paging.NextPage = dto.Paging.Page + 1
-	if dto.Paging.NextPage > last {
-		dto.Paging.NextPage = last
+	paging.NextPage = paging.Page + 1
+	if paging.NextPage > last {
+		paging.NextPage = last
 	}
 
-	if err = db.Select(resultSet, sql, args...); err != nil {
-		return dto, err
+	return paging
+}
+
+// Filters within group are joined with OR, groups with AND
+// Query callbacks and HAVING of aggregated fields are returned as callbacks on top of the query
+func filterQueries(d Dialect, model Grid, groups [][]Filter, location *time.Location) (squirrel.And, callbackStack, error) {
+	filterCalls := map[string]FilterCallback{}
+	fcl, ok := interface{}(model).(FilterCallbacks)
+	if ok {
+		filterCalls = fcl.FilterCallbacks()
 	}
 
-	dto.Items = resultSet
+	queryCalls := map[string]QueryCallback{}
+	qcl, ok := interface{}(model).(QueryCallbacks)
+	if ok {
+		queryCalls = qcl.QueryCallbacks()
+	}
 
-	return dto, nil
+	callbacks := callbackStack{}
+
+	// Filters
+	andQueries := squirrel.And{}
+	for _, filters := range groups {
+		var orQeuries squirrel.Or
+		var having squirrel.Or
+		for _, filter := range filters {
+			if hasTag(model, filter.Column, filterable) {
+				if err := checkOperator(model, filter); err != nil {
+					return nil, nil, err
+				}
+
+				filter, err := resolveDates(model, filter, location)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				tagName := taggedName(model, filter.Column)
+				if callback, ok := filterCalls[tagName]; ok {
+					orQeuries = append(orQeuries, callback(tagName, filter.Operator, filter.Value))
+				} else if callback, ok := queryCalls[tagName]; ok {
+					callbacks = append(callbacks, callbackStackItem{
+						callback: callback,
+						field:    tagName,
+						operator: filter.Operator,
+						values:   filter.Value,
+					})
+				} else {
+					query, err := filterQuery(d, model, filter)
+					if err != nil {
+						return nil, nil, err
+					}
+
+					if _, ok := tagValue(model, filter.Column, aggregate); ok {
+						having = append(having, query)
+					} else {
+						orQeuries = append(orQeuries, query)
+					}
+				}
+			} else {
+				return nil, nil, fmt.Errorf("field [%s] is not tagged for filtering", filter.Column)
+			}
+		}
+
+		// Aggregated fields are filtered within HAVING
+		if having != nil {
+			if orQeuries != nil {
+				return nil, nil, errors.New("aggregated fields can't be grouped with other filters")
+			}
+
+			callbacks = append(callbacks, callbackStackItem{
+				callback: func(qb squirrel.SelectBuilder, _, _ string, _ []string) squirrel.SelectBuilder {
+					return qb.Having(having)
+				},
+			})
+		}
+
+		if orQeuries != nil {
+			andQueries = append(andQueries, orQeuries)
+		}
+	}
+
+	return andQueries, callbacks, nil
+}
+
+func filterQuery(d Dialect, model Grid, filter Filter) (squirrel.Sqlizer, error) {
+	// Negative filter of related rows means none of them matches the positive one
+	negate := false
+	if positive, ok := negatedOperators[filter.Operator]; ok && hasTag(model, filter.Column, relation) {
+		filter.Operator = positive
+		negate = true
+	}
+
+	var query squirrel.Sqlizer
+	if containment(filter.Operator) {
+		var err error
+		if query, err = containsQuery(d, model, filter); err != nil {
+			return nil, err
+		}
+	} else {
+		column, safe, err := columnExpression(d, model, filter.Column)
+		if err != nil {
+			return nil, err
+		}
+
+		collation, _ := tagValue(model, filter.Column, collate)
+		query = d.Collated(column, collation, filter.Operator, filter.Value, safe)
+	}
+
+	return relationQuery(model, filter.Column, query, negate)
+}
+
+// SQL expression of filtered or sorted column and whether it still needs quoting
+func columnExpression(d Dialect, model Grid, column string) (string, bool, error) {
+	if expression, ok := tagValue(model, column, aggregate); ok {
+		if !balanced(expression) {
+			return "", false, fmt.Errorf("invalid aggregate [%s] of field [%s]", expression, column)
+		}
+
+		return expression, false, nil
+	}
+
+	name := taggedName(model, column)
+	path, err := jsonPathOf(model, column)
+	if err != nil {
+		return "", false, err
+	}
+
+	if path != "" {
+		return d.JSONValue(d.Name(name, true), path), false, nil
+	}
+
+	return name, true, nil
 }
 
 func FormQuery(field, operator string, values []string, safe bool) squirrel.Sqlizer {
-	return squirrel.Expr(
-		OperatorToQuery(operator, field, len(values), safe),
-		ParseValues(values, operator)...,
-	)
+	return MySQL.FormQuery(field, operator, values, safe)
 }
 
 func OperatorToQuery(operator, column string, values int, safe bool) string {
+	return MySQL.OperatorToQuery(operator, column, values, safe)
+}
+
+func operatorToQuery(operator, name string, values int) string {
 	switch operator {
 	case Eq:
-		return fmt.Sprintf("%s = ?", Name(column, safe))
+		return fmt.Sprintf("%s = ?", name)
 	case Neq:
-		return fmt.Sprintf("%s != ?", Name(column, safe))
+		return fmt.Sprintf("%s != ?", name)
 	case Empty:
-		return fmt.Sprintf("%s IS NULL", Name(column, safe))
+		return fmt.Sprintf("%s IS NULL", name)
 	case Nempty:
-		return fmt.Sprintf("%s IS NOT NULL", Name(column, safe))
+		return fmt.Sprintf("%s IS NOT NULL", name)
 	case In:
 		vals := make([]string, values)
 		for i := 0; i < values; i++ {
 			vals[i] = "?"
 		}
-		return fmt.Sprintf("%s IN (%s)", Name(column, safe), strings.Join(vals, ","))
+		return fmt.Sprintf("%s IN (%s)", name, strings.Join(vals, ","))
 	case Nin:
 		vals := make([]string, values)
 		for i := 0; i < values; i++ {
 			vals[i] = "?"
 		}
-		return fmt.Sprintf("%s NOT IN (%s)", Name(column, safe), strings.Join(vals, ","))
+		return fmt.Sprintf("%s NOT IN (%s)", name, strings.Join(vals, ","))
 	case Like:
-		return fmt.Sprintf("%s LIKE ?", Name(column, safe))
+		return fmt.Sprintf("%s LIKE ?", name)
 	case Starts:
-		return fmt.Sprintf("%s LIKE ?", Name(column, safe))
+		return fmt.Sprintf("%s LIKE ?", name)
 	case Ends:
-		return fmt.Sprintf("%s LIKE ?", Name(column, safe))
+		return fmt.Sprintf("%s LIKE ?", name)
 	case Nlike:
-		return fmt.Sprintf("%s NOT LIKE ?", Name(column, safe))
+		return fmt.Sprintf("%s NOT LIKE ?", name)
 	case Gt:
-		return fmt.Sprintf("%s > ?", Name(column, safe))
+		return fmt.Sprintf("%s > ?", name)
 	case Lt:
-		return fmt.Sprintf("%s < ?", Name(column, safe))
+		return fmt.Sprintf("%s < ?", name)
 	case Lte:
-		return fmt.Sprintf("%s <= ?", Name(column, safe))
+		return fmt.Sprintf("%s <= ?", name)
 	case Gte:
-		return fmt.Sprintf("%s >= ?", Name(column, safe))
+		return fmt.Sprintf("%s >= ?", name)
 	case Between:
-		return fmt.Sprintf("%s BETWEEN ? AND ?", Name(column, safe))
+		return fmt.Sprintf("%s BETWEEN ? AND ?", name)
 	case Nbetween:
-		return fmt.Sprintf("%s NOT BETWEEN ? AND ?", Name(column, safe))
+		return fmt.Sprintf("%s NOT BETWEEN ? AND ?", name)
 	}
 
-	return fmt.Sprintf("%s = ?", Name(column, safe))
+	return fmt.Sprintf("%s = ?", name)
 }
 
 func Name(name string, safe bool) string {
-	if safe {
-		cols := strings.Split(name, ".")
-		for i, col := range cols {
-			cols[i] = fmt.Sprintf("`%s`", col)
-		}
-
-		return strings.Join(cols, ".")
-	}
-
-	return name
+	return MySQL.Name(name, safe)
 }
 
 func ParseValues(values []string, operator string) []interface{} {
 	vals := make([]interface{}, len(values))
 	for key, val := range values {
 		switch operator {
-		case Like:
+		case Like, Ilike:
 			vals[key] = fmt.Sprintf("%%%s%%", val)
 		case Nlike:
 			vals[key] = fmt.Sprintf("%%%s%%", val)
-		case Starts:
+		case Starts, Istarts:
 			vals[key] = fmt.Sprintf("%s%%", val)
-		case Ends:
+		case Ends, Iends:
 			vals[key] = fmt.Sprintf("%%%s", val)
 		case Empty, Nempty:
 			return nil
@@ -346,7 +529,8 @@ func ParseValues(values []string, operator string) []interface{} {
 	return vals
 }
 
-func createSelects(model Grid, selects string) squirrel.SelectBuilder {
+// Selects all fields or only selected ones, custom selects of SearchQuery are kept
+func createSelects(model Grid, d Dialect, selects string, selected map[string]bool) squirrel.SelectBuilder {
 	var listed []string
 	for _, field := range strings.Split(selects, ",") {
 		field = strings.TrimSpace(field)
@@ -365,7 +549,13 @@ func createSelects(model Grid, selects string) squirrel.SelectBuilder {
 	for i := 0; i < count; i++ {
 		fieldName := fType.Field(i).Tag.Get("db")
 
-		if !hasTag(model, fType.Field(i).Name, skip) {
+		// Fields left out by projection (`_fields`)
+		if selected != nil && !selected[fType.Field(i).Name] {
+			continue
+		}
+
+		// Relation fields are not joined within main query
+		if !hasTag(model, fType.Field(i).Name, skip) && !hasTag(model, fType.Field(i).Name, relation) {
 			if fieldName == "" {
 				fieldName = lowerFirst(fType.Field(i).Name)
 			}
@@ -379,16 +569,53 @@ func createSelects(model Grid, selects string) squirrel.SelectBuilder {
 			}
 
 			if ok {
-				fields = append(fields, fmt.Sprintf("%s as `%s`", Name(fieldName, true), fieldName))
+				column := d.Name(fieldName, true)
+				if path, err := jsonPathOf(model, fType.Field(i).Name); err == nil && path != "" {
+					column = d.JSONValue(column, path)
+				}
+				if expression, ok := tagValue(model, fType.Field(i).Name, aggregate); ok {
+					column = expression
+				}
+
+				fields = append(fields, fmt.Sprintf("%s as %s", column, d.quote(fieldName)))
 			}
 		}
 	}
 
-	return model.SearchQuery(squirrel.Select(fields...))
+	return model.SearchQuery(squirrel.Select(fields...).PlaceholderFormat(d.placeholder()))
+}
+
+// Field may whitelist operators (`filter=EQ|IN|REGEX`), regular expressions must be always whitelisted
+func checkOperator(model Grid, filter Filter) error {
+	allowed, ok := tagValue(model, filter.Column, filterable)
+	regex := filter.Operator == Regex || filter.Operator == Nregex
+	if ok || regex {
+		found := false
+		for _, operator := range strings.Split(allowed, "|") {
+			if strings.TrimSpace(operator) == filter.Operator {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("operator [%s] is not allowed for field [%s]", filter.Operator, filter.Column)
+		}
+	}
+
+	if regex {
+		for _, value := range filter.Value {
+			if utf8.RuneCountInString(value) > RegexMaxLength {
+				return fmt.Errorf("pattern for field [%s] exceeds %d characters", filter.Column, RegexMaxLength)
+			}
+		}
+	}
+
+	return nil
 }
 
 func taggedName(model Grid, column string) string {
-	field, ok := reflect.TypeOf(model).FieldByName(strings.Title(column))
+	field, ok := gridField(model, column)
 	if !ok {
 		return column
 	}
@@ -398,22 +625,102 @@ func taggedName(model Grid, column string) string {
 		return name
 	}
 
-	return column
+	return strings.SplitN(column, ".", 2)[0]
 }
 
 func hasTag(model Grid, column, operation string) bool {
-	field, ok := reflect.TypeOf(model).FieldByName(strings.Title(column))
+	field, ok := gridField(model, column)
 	if !ok {
 		return false
 	}
 
-	for _, tag := range strings.Split(field.Tag.Get("grid"), ",") {
-		if strings.Trim(tag, " ") == operation {
-			return true
+	_, _, ok = fieldTag(field, operation)
+
+	return ok
+}
+
+// Reads value of `option=value` within grid tag
+func tagValue(model Grid, column, option string) (string, bool) {
+	field, ok := gridField(model, column)
+	if !ok {
+		return "", false
+	}
+
+	value, hasValue, _ := fieldTag(field, option)
+
+	return value, hasValue
+}
+
+// Finds struct field of column, keys of JSON fields (attrs.color) belong to the field itself
+func gridField(model Grid, column string) (reflect.StructField, bool) {
+	fType := reflect.TypeOf(model)
+	if field, ok := fType.FieldByName(strings.Title(column)); ok {
+		return field, true
+	}
+
+	parts := strings.SplitN(column, ".", 2)
+	if len(parts) == 2 {
+		if field, ok := fType.FieldByName(strings.Title(parts[0])); ok {
+			if _, _, isJSON := fieldTag(field, jsonPath); isJSON {
+				return field, true
+			}
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+func fieldTag(field reflect.StructField, option string) (string, bool, bool) {
+	for _, tag := range tagOptions(field.Tag.Get("grid")) {
+		parts := strings.SplitN(tag, "=", 2)
+		if strings.Trim(parts[0], " ") == option {
+			if len(parts) == 2 {
+				return strings.Trim(parts[1], " "), true, true
+			}
+
+			return "", false, true
+		}
+	}
+
+	return "", false, false
+}
+
+// Options are separated by commas outside of parentheses, so expressions like ROUND(AVG(x), 2) are kept whole
+func tagOptions(tag string) []string {
+	var options []string
+	depth, start := 0, 0
+	for i, r := range tag {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				options = append(options, tag[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(options, tag[start:])
+}
+
+func balanced(expression string) bool {
+	depth := 0
+	for _, r := range expression {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth < 0 {
+			return false
 		}
 	}
 
-	return false
+	return depth == 0
 }
 
 func getSearchFields(model Grid) []string {
@@ -0,0 +1,345 @@
+package filter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	Hour  = "hour"
+	Day   = "day"
+	Week  = "week"
+	Month = "month"
+	Year  = "year"
+
+	groupable  = "group"
+	groupCount = "count"
+)
+
+var bucketFormats = map[string]string{
+	Hour:  "%Y-%m-%d %H:00:00",
+	Day:   "%Y-%m-%d",
+	Week:  "%Y-%m-%d",
+	Month: "%Y-%m-01",
+	Year:  "%Y-01-01",
+}
+
+type Bucket struct {
+	Column string `json:"column"`
+	Unit   string `json:"unit"`
+}
+
+// Keys are returned as strings, buckets by their first day (or hour) in request location
+type GroupRow struct {
+	Keys       map[string]*string `json:"keys"`
+	Count      int                `json:"count"`
+	Aggregates AggregateValues    `json:"aggregates,omitempty"`
+}
+
+type groupKey struct {
+	column     string
+	expression string
+}
+
+// Rows of filtered grid grouped by `group` fields and time bucket, counted and aggregated
+// Groups are paged and sorted by keys, `count` or aggregates (`amount.sum`), Items are []GroupRow
+func GetGroups(model Grid, dto GridDto, db Executor) (GridDto, error) {
+	return GetGroupsContext(context.Background(), model, dto, db)
+}
+
+func GetGroupsContext(ctx context.Context, model Grid, dto GridDto, db Executor) (GridDto, error) {
+	err := inSnapshot(ctx, queryMode(model), db, func(db Executor) (err error) {
+		dto, err = getGroups(ctx, model, dto, db)
+
+		return err
+	})
+
+	return dto, err
+}
+
+func getGroups(ctx context.Context, model Grid, dto GridDto, db Executor) (GridDto, error) {
+	if dto.Paging.Size <= 0 {
+		dto.Paging.Size = defaultSize
+	}
+	if dto.Paging.Page <= 0 {
+		dto.Paging.Page = 1
+	}
+
+	d := dialectOf(db)
+	location, err := requestLocation(dto)
+	if err != nil {
+		return dto, err
+	}
+
+	keys, err := groupKeys(d, model, dto, location)
+	if err != nil {
+		return dto, err
+	}
+
+	for _, aggregate := range dto.Aggregate {
+		if err := checkAggregate(model, aggregate); err != nil {
+			return dto, err
+		}
+	}
+
+	where, callbacks, err := filterQueries(d, model, dto.Filter, location)
+	if err != nil {
+		return dto, err
+	}
+
+	search, err := searchQuery(d, model, tokenize(dto.Search))
+	if err != nil {
+		return dto, err
+	}
+	if search != nil {
+		where = append(where, search)
+	}
+
+	qb, err := groupQuery(d, model, keys, dto.Aggregate, where, callbacks)
+	if err != nil {
+		return dto, err
+	}
+
+	sqlC, argsC, err := squirrel.Select("COUNT(*)").PlaceholderFormat(d.placeholder()).FromSelect(qb, "counter_alias").ToSql()
+	if err != nil {
+		return dto, err
+	}
+
+	var count []int
+	if err = sqlx.SelectContext(ctx, db, &count, sqlC, argsC...); err != nil {
+		return dto, err
+	}
+
+	if qb, err = orderGroups(d, keys, dto.Aggregate, dto.Sorter, qb); err != nil {
+		return dto, err
+	}
+
+	query, args, err := qb.Limit(uint64(dto.Paging.Size)).
+		Offset(uint64((dto.Paging.Page - 1) * dto.Paging.Size)).
+		ToSql()
+	if err != nil {
+		return dto, err
+	}
+
+	dto.Paging = paginate(dto.Paging, count[0])
+
+	rows, err := db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return dto, err
+	}
+	defer rows.Close()
+
+	items, err := scanGroups(rows, keys, dto.Aggregate)
+	if err != nil {
+		return dto, err
+	}
+
+	dto.Items = items
+
+	return dto, nil
+}
+
+// Buckets are formed within request location
+func groupKeys(d Dialect, model Grid, dto GridDto, location *time.Location) ([]groupKey, error) {
+	if len(dto.Group) == 0 && dto.Bucket == nil {
+		return nil, errors.New("grouping requires group or bucket")
+	}
+
+	var keys []groupKey
+	for _, column := range dto.Group {
+		if err := checkSelectable(model, column); err != nil {
+			return nil, err
+		}
+		if !hasTag(model, column, groupable) {
+			return nil, fmt.Errorf("field [%s] is not tagged for grouping", column)
+		}
+
+		expression, safe, err := columnExpression(d, model, column)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, groupKey{column: column, expression: d.Name(expression, safe)})
+	}
+
+	if bucket := dto.Bucket; bucket != nil {
+		if err := checkSelectable(model, bucket.Column); err != nil {
+			return nil, err
+		}
+		if !hasTag(model, bucket.Column, groupable) || !isTimeField(model, bucket.Column) {
+			return nil, fmt.Errorf("field [%s] is not time field tagged for grouping", bucket.Column)
+		}
+		if _, ok := bucketFormats[bucket.Unit]; !ok {
+			return nil, fmt.Errorf("invalid bucket [%s] of field [%s]", bucket.Unit, bucket.Column)
+		}
+
+		expression, safe, err := columnExpression(d, model, bucket.Column)
+		if err != nil {
+			return nil, err
+		}
+
+		column := d.inLocation(d.Name(expression, safe), location)
+		keys = append(keys, groupKey{column: bucket.Column, expression: d.Bucket(column, bucket.Unit)})
+	}
+
+	return keys, nil
+}
+
+// Filtered query (HAVING included) is wrapped so grouped and aggregated fields of grid may be grouped again
+func groupQuery(d Dialect, model Grid, keys []groupKey, aggregates []Aggregate, where squirrel.And, callbacks callbackStack) (squirrel.SelectBuilder, error) {
+	var columns, groups, outer []string
+	for i, key := range keys {
+		alias := d.quote(fmt.Sprintf("g%d", i))
+		columns = append(columns, fmt.Sprintf("%s AS %s", key.expression, alias))
+		groups = append(groups, alias)
+	}
+	outer = append(outer, groups...)
+	outer = append(outer, fmt.Sprintf("COUNT(*) AS %s", d.quote(groupCount)))
+
+	for i, aggregate := range aggregates {
+		expression, safe, err := columnExpression(d, model, aggregate.Column)
+		if err != nil {
+			return squirrel.SelectBuilder{}, err
+		}
+
+		alias := d.quote(fmt.Sprintf("a%d", i))
+		columns = append(columns, fmt.Sprintf("%s AS %s", d.Name(expression, safe), alias))
+		outer = append(outer, fmt.Sprintf("%s(%s) AS %s", footerFunctions[aggregate.Function], alias, alias))
+	}
+
+	inner := model.SearchQuery(squirrel.Select(columns...)).Where(where)
+
+	return squirrel.Select(outer...).
+		PlaceholderFormat(d.placeholder()).
+		FromSelect(callbacks.merge(inner), "grouped").
+		GroupBy(groups...), nil
+}
+
+// Sorters refer to keys, count or aggregates (amount.sum), remaining keys keep the order stable
+func orderGroups(d Dialect, keys []groupKey, aggregates []Aggregate, sorters []Sorter, qb squirrel.SelectBuilder) (squirrel.SelectBuilder, error) {
+	aliases := map[string]string{groupCount: d.quote(groupCount)}
+	for i, key := range keys {
+		aliases[key.column] = d.quote(fmt.Sprintf("g%d", i))
+	}
+	for i, aggregate := range aggregates {
+		aliases[fmt.Sprintf("%s.%s", aggregate.Column, aggregate.Function)] = d.quote(fmt.Sprintf("a%d", i))
+	}
+
+	sorted := map[string]bool{}
+	for _, sorter := range sorters {
+		alias, ok := aliases[sorter.Column]
+		if !ok {
+			return qb, fmt.Errorf("field [%s] is not grouped or aggregated", sorter.Column)
+		}
+
+		direction, nulls, err := sortDirection(sorter)
+		if err != nil {
+			return qb, err
+		}
+
+		qb = qb.OrderBy(d.orderBy(alias, direction, nulls)...)
+		sorted[sorter.Column] = true
+	}
+
+	for _, key := range keys {
+		if !sorted[key.column] {
+			qb = qb.OrderBy(fmt.Sprintf("%s %s", aliases[key.column], Asc))
+		}
+	}
+
+	return qb, nil
+}
+
+func scanGroups(rows *sqlx.Rows, keys []groupKey, aggregates []Aggregate) ([]GroupRow, error) {
+	items := make([]GroupRow, 0)
+	for rows.Next() {
+		values := make([]sql.NullString, len(keys))
+		functions := make([]sql.NullFloat64, len(aggregates))
+		row := GroupRow{Keys: map[string]*string{}}
+
+		dest := []interface{}{}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		dest = append(dest, &row.Count)
+		for i := range functions {
+			dest = append(dest, &functions[i])
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		for i, key := range keys {
+			row.Keys[key.column] = nil
+			if values[i].Valid {
+				value := values[i].String
+				row.Keys[key.column] = &value
+			}
+		}
+
+		if len(aggregates) > 0 {
+			row.Aggregates = aggregateValues(aggregates, functions)
+		}
+
+		items = append(items, row)
+	}
+
+	return items, rows.Err()
+}
+
+// Converts datetime stored in DatabaseLocation into location, named zones require time zone tables on MySQL
+func (d Dialect) inLocation(column string, location *time.Location) string {
+	if location.String() == DatabaseLocation.String() {
+		return column
+	}
+
+	if d == PostgreSQL {
+		return fmt.Sprintf("((%s AT TIME ZONE %s) AT TIME ZONE %s)", column, d.zone(DatabaseLocation), d.zone(location))
+	}
+
+	return fmt.Sprintf("CONVERT_TZ(%s, %s, %s)", column, d.zone(DatabaseLocation), d.zone(location))
+}
+
+// Local and UTC have no name known to database, their current offset is used
+func (d Dialect) zone(location *time.Location) string {
+	name := location.String()
+	if name != "Local" && name != "UTC" {
+		return fmt.Sprintf("'%s'", strings.ReplaceAll(name, "'", "''"))
+	}
+
+	_, offset := Clock().In(location).Zone()
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	interval := fmt.Sprintf("%s%02d:%02d", sign, offset/3600, offset%3600/60)
+
+	if d == PostgreSQL {
+		return fmt.Sprintf("INTERVAL '%s'", interval)
+	}
+
+	return fmt.Sprintf("'%s'", interval)
+}
+
+// Formatted start of the time bucket, weeks start on Monday
+func (d Dialect) Bucket(column, unit string) string {
+	if d == PostgreSQL {
+		format := strings.NewReplacer("%Y", "YYYY", "%m", "MM", "%d", "DD", "%H", "HH24").Replace(bucketFormats[unit])
+
+		return fmt.Sprintf("to_char(date_trunc('%s', %s), '%s')", unit, column, format)
+	}
+
+	if unit == Week {
+		column = fmt.Sprintf("DATE_SUB(%s, INTERVAL WEEKDAY(%s) DAY)", column, column)
+	}
+
+	return fmt.Sprintf("DATE_FORMAT(%s, '%s')", column, bucketFormats[unit])
+}
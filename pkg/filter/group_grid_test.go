@@ -0,0 +1,120 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type groupGrid struct {
+	Id        int       `db:"t.id" grid:"filter,sort,footer"`
+	FileId    int       `db:"t.file_id" grid:"filter,group"`
+	Name      string    `db:"t.Name" grid:"filter,group"`
+	CreatedAt time.Time `db:"t.created_at" grid:"filter,group,skip"`
+}
+
+func (T groupGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("tag as t")
+}
+
+func Test_GroupQuery(t *testing.T) {
+	dto := GridDto{Group: []string{"name"}, Bucket: &Bucket{Column: "createdAt", Unit: Week}}
+	keys, err := groupKeys(MySQL, groupGrid{}, dto, Location)
+	require.Nil(t, err)
+
+	where, callbacks, err := filterQueries(MySQL, groupGrid{}, [][]Filter{
+		{{Column: "fileId", Operator: Eq, Value: []string{"1"}}},
+	}, Location)
+	require.Nil(t, err)
+
+	aggregates := []Aggregate{{Column: "id", Function: Sum}}
+	qb, err := groupQuery(MySQL, groupGrid{}, keys, aggregates, where, callbacks)
+	require.Nil(t, err)
+
+	qb, err = orderGroups(MySQL, keys, aggregates, []Sorter{{Column: "id.sum", Direction: Desc}}, qb)
+	require.Nil(t, err)
+
+	sql, args, err := qb.ToSql()
+	require.Nil(t, err)
+	assert.Equal(
+		t,
+		"SELECT `g0`, `g1`, COUNT(*) AS `count`, SUM(`a0`) AS `a0` FROM (SELECT `t`.`Name` AS `g0`, "+
+			"DATE_FORMAT(DATE_SUB(`t`.`created_at`, INTERVAL WEEKDAY(`t`.`created_at`) DAY), '%Y-%m-%d') AS `g1`, "+
+			"`t`.`id` AS `a0` FROM tag as t WHERE ((`t`.`file_id` = ?))) AS grouped "+
+			"GROUP BY `g0`, `g1` ORDER BY `a0` DESC, `g0` ASC, `g1` ASC",
+		sql,
+	)
+	assert.Equal(t, []interface{}{"1"}, args)
+
+	assert.Equal(t, `to_char(date_trunc('month', "t"."created_at"), 'YYYY-MM-01')`, PostgreSQL.Bucket(`"t"."created_at"`, Month))
+	assert.Equal(t, "DATE_FORMAT(t.created_at, '%Y-%m-%d %H:00:00')", MySQL.Bucket("t.created_at", Hour))
+
+	_, err = orderGroups(MySQL, keys, aggregates, []Sorter{{Column: "fileId"}}, qb)
+	assert.EqualError(t, err, "field [fileId] is not grouped or aggregated")
+
+	_, err = groupKeys(MySQL, groupGrid{}, GridDto{Group: []string{"id"}}, Location)
+	assert.EqualError(t, err, "field [id] is not tagged for grouping")
+
+	_, err = groupKeys(MySQL, groupGrid{}, GridDto{Bucket: &Bucket{Column: "name", Unit: Day}}, Location)
+	assert.EqualError(t, err, "field [name] is not time field tagged for grouping")
+
+	_, err = groupKeys(MySQL, groupGrid{}, GridDto{Bucket: &Bucket{Column: "createdAt", Unit: "decade"}}, Location)
+	assert.EqualError(t, err, "invalid bucket [decade] of field [createdAt]")
+
+	_, err = groupKeys(MySQL, groupGrid{}, GridDto{}, Location)
+	assert.EqualError(t, err, "grouping requires group or bucket")
+}
+
+func Test_GroupBucketInTimezone(t *testing.T) {
+	withClock(t, time.Date(2024, 3, 14, 12, 0, 0, 0, time.UTC))
+
+	prague, err := time.LoadLocation("Europe/Prague")
+	require.Nil(t, err)
+
+	dto := GridDto{Bucket: &Bucket{Column: "createdAt", Unit: Day}}
+	keys, err := groupKeys(MySQL, groupGrid{}, dto, prague)
+	require.Nil(t, err)
+	assert.Equal(t, "DATE_FORMAT(CONVERT_TZ(`t`.`created_at`, '+00:00', 'Europe/Prague'), '%Y-%m-%d')", keys[0].expression)
+
+	keys, err = groupKeys(PostgreSQL, groupGrid{}, dto, prague)
+	require.Nil(t, err)
+	assert.Equal(
+		t,
+		`to_char(date_trunc('day', (("t"."created_at" AT TIME ZONE INTERVAL '+00:00') AT TIME ZONE 'Europe/Prague')), 'YYYY-MM-DD')`,
+		keys[0].expression,
+	)
+
+	keys, err = groupKeys(MySQL, groupGrid{}, dto, time.UTC)
+	require.Nil(t, err)
+	assert.Equal(t, "DATE_FORMAT(`t`.`created_at`, '%Y-%m-%d')", keys[0].expression)
+}
+
+func Test_GroupGrid(t *testing.T) {
+	prepareTestData(t)
+
+	dto := GridDto{
+		Group:     []string{"fileId", "name"},
+		Aggregate: []Aggregate{{Column: "id", Function: Sum}},
+		Sorter:    []Sorter{{Column: "name", Direction: Desc}},
+	}
+
+	dto, err := GetGroups(groupGrid{}, dto, MariaDB)
+	require.Nil(t, err)
+
+	file, losos, other := "1", "Losos", "22"
+	first, second := 1.0, 2.0
+	assert.Equal(t, 2, dto.Paging.Total)
+	assert.Equal(t, []GroupRow{
+		{Keys: map[string]*string{"fileId": &file, "name": &losos}, Count: 1, Aggregates: AggregateValues{"id": {Sum: &first}}},
+		{Keys: map[string]*string{"fileId": &file, "name": &other}, Count: 1, Aggregates: AggregateValues{"id": {Sum: &second}}},
+	}, dto.Items)
+
+	dto = GridDto{Group: []string{"fileId"}}
+	dto, err = GetGroups(groupGrid{}, dto, MariaDB)
+	require.Nil(t, err)
+
+	assert.Equal(t, []GroupRow{{Keys: map[string]*string{"fileId": &file}, Count: 2}}, dto.Items)
+}
@@ -0,0 +1,151 @@
+package filter
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+const (
+	defaultHighlightPre    = "<em>"
+	defaultHighlightPost   = "</em>"
+	defaultHighlightLength = 120
+	ellipsis               = "…"
+)
+
+type Highlight struct {
+	Fields   []string          `json:"fields"`
+	Snippets map[string]string `json:"snippets"`
+}
+
+type Highlighting struct {
+	Pre    string
+	Post   string
+	Length int
+}
+
+/// Overrides markers wrapping matched terms and maximal length of snippets
+type Highlighters interface {
+	Highlighting() Highlighting
+}
+
+// Lists matched search fields with highlighted snippets for every loaded item
+func highlightItems(model Grid, items interface{}, tokens []searchToken) []Highlight {
+	options := Highlighting{
+		Pre:    defaultHighlightPre,
+		Post:   defaultHighlightPost,
+		Length: defaultHighlightLength,
+	}
+	if hl, ok := interface{}(model).(Highlighters); ok {
+		options = hl.Highlighting()
+	}
+
+	var terms [][]rune
+	for _, token := range tokens {
+		if !token.exclude {
+			terms = append(terms, []rune(strings.Map(unicode.ToLower, token.value)))
+		}
+	}
+
+	rows := reflect.Indirect(reflect.ValueOf(items))
+	if rows.Kind() != reflect.Slice {
+		return nil
+	}
+
+	fields := getSearchFields(model)
+	highlights := make([]Highlight, rows.Len())
+	for i := 0; i < rows.Len(); i++ {
+		highlights[i] = Highlight{Fields: []string{}, Snippets: map[string]string{}}
+
+		row := reflect.Indirect(rows.Index(i))
+		if row.Kind() != reflect.Struct {
+			continue
+		}
+
+		for _, field := range fields {
+			value := row.FieldByName(strings.Title(field))
+			if !value.IsValid() {
+				continue
+			}
+			if value.Kind() == reflect.Ptr {
+				if value.IsNil() {
+					continue
+				}
+				value = value.Elem()
+			}
+
+			if snippet, ok := highlightValue(fmt.Sprint(value.Interface()), terms, options); ok {
+				highlights[i].Fields = append(highlights[i].Fields, field)
+				highlights[i].Snippets[field] = snippet
+			}
+		}
+	}
+
+	return highlights
+}
+
+// Text around markers is HTML escaped, so the snippet is safe to render as markup
+func highlightValue(value string, terms [][]rune, options Highlighting) (string, bool) {
+	text := []rune(value)
+	lower := make([]rune, len(text))
+	for i, r := range text {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	marked := make([]bool, len(text))
+	first := -1
+	for _, term := range terms {
+		if len(term) == 0 {
+			continue
+		}
+		for i := 0; i+len(term) <= len(lower); i++ {
+			if string(lower[i:i+len(term)]) != string(term) {
+				continue
+			}
+			for j := i; j < i+len(term); j++ {
+				marked[j] = true
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+		}
+	}
+
+	if first < 0 {
+		return "", false
+	}
+
+	start, end := 0, len(text)
+	if options.Length > 0 && len(text) > options.Length {
+		start = first - options.Length/4
+		if start < 0 {
+			start = 0
+		}
+		end = start + options.Length
+		if end > len(text) {
+			end = len(text)
+			start = end - options.Length
+		}
+	}
+
+	var snippet strings.Builder
+	if start > 0 {
+		snippet.WriteString(ellipsis)
+	}
+	for i := start; i < end; i++ {
+		if marked[i] && (i == start || !marked[i-1]) {
+			snippet.WriteString(options.Pre)
+		}
+		snippet.WriteString(html.EscapeString(string(text[i])))
+		if marked[i] && (i == end-1 || !marked[i+1]) {
+			snippet.WriteString(options.Post)
+		}
+	}
+	if end < len(text) {
+		snippet.WriteString(ellipsis)
+	}
+
+	return snippet.String(), true
+}
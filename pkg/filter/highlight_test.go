@@ -0,0 +1,37 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Highlight(t *testing.T) {
+	items := []searchGrid{
+		{Id: 1, Name: "John", Surname: "Johnson"},
+		{Id: 2, Name: "Jane", Surname: "Smith"},
+	}
+
+	assert.Equal(t, []Highlight{
+		{
+			Fields:   []string{"Name", "Surname"},
+			Snippets: map[string]string{"Name": "<em>John</em>", "Surname": "<em>John</em>son"},
+		},
+		{
+			Fields:   []string{},
+			Snippets: map[string]string{},
+		},
+	}, highlightItems(searchGrid{}, &items, tokenize("john -jane")))
+
+	options := Highlighting{Pre: "[", Post: "]", Length: 12}
+	snippet, ok := highlightValue("Příliš žluťoučký kůň úpěl ďábelské ódy", [][]rune{[]rune("kůň"), []rune("úp")}, options)
+	assert.True(t, ok)
+	assert.Equal(t, "…ký [kůň] [úp]ěl …", snippet)
+
+	snippet, ok = highlightValue(`<img src=x onerror="alert('john')">`, [][]rune{[]rune("john")}, Highlighting{Pre: "<em>", Post: "</em>"})
+	assert.True(t, ok)
+	assert.Equal(t, "&lt;img src=x onerror=&#34;alert(&#39;<em>john</em>&#39;)&#34;&gt;", snippet)
+
+	_, ok = highlightValue("Losos", [][]rune{[]rune("pstruh")}, options)
+	assert.False(t, ok)
+}
@@ -0,0 +1,55 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const jsonPath = "json"
+
+var jsonPathPattern = regexp.MustCompile(`^\$(\.[A-Za-z_][A-Za-z0-9_]*|\[[0-9]+\])*$`)
+
+// Path within JSON field declared by `json=$.path`, requested `column.path` key is appended to it
+func jsonPathOf(model Grid, column string) (string, error) {
+	field, ok := gridField(model, column)
+	if !ok {
+		return "", nil
+	}
+
+	declared, _, isJSON := fieldTag(field, jsonPath)
+	if !isJSON {
+		return "", nil
+	}
+
+	path := declared
+	if parts := strings.SplitN(column, ".", 2); len(parts) == 2 {
+		if path == "" {
+			path = "$"
+		}
+		path = fmt.Sprintf("%s.%s", path, parts[1])
+	}
+
+	if path == "" || path == "$" {
+		return "", nil
+	}
+
+	if !jsonPathPattern.MatchString(path) {
+		return "", fmt.Errorf("invalid JSON path [%s] of field [%s]", path, column)
+	}
+
+	return path, nil
+}
+
+// Extracts scalar value of path, path must be already validated
+func (d Dialect) JSONValue(name, path string) string {
+	if d == PostgreSQL {
+		keys := strings.FieldsFunc(strings.TrimPrefix(path, "$"), func(r rune) bool {
+			return r == '.' || r == '[' || r == ']'
+		})
+
+		return fmt.Sprintf("%s #>> '{%s}'", name, strings.Join(keys, ","))
+	}
+
+	return fmt.Sprintf("JSON_VALUE(%s, '%s')", name, path)
+}
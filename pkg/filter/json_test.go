@@ -0,0 +1,53 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonGrid struct {
+	Id    int    `db:"p.id" grid:"filter,sort"`
+	Attrs string `db:"p.attrs" grid:"filter,sort,json,skip"`
+	Color string `db:"p.attrs" grid:"filter,sort,json=$.color"`
+}
+
+func (T jsonGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("product p")
+}
+
+func Test_JSONColumns(t *testing.T) {
+	column, safe, err := columnExpression(MySQL, jsonGrid{}, "attrs.size[0].label")
+	require.Nil(t, err)
+	assert.False(t, safe)
+	assert.Equal(t, "JSON_VALUE(`p`.`attrs`, '$.size[0].label')", column)
+
+	column, _, err = columnExpression(PostgreSQL, jsonGrid{}, "attrs.size[0].label")
+	require.Nil(t, err)
+	assert.Equal(t, `"p"."attrs" #>> '{size,0,label}'`, column)
+
+	column, _, err = columnExpression(MySQL, jsonGrid{}, "color")
+	require.Nil(t, err)
+	assert.Equal(t, "JSON_VALUE(`p`.`attrs`, '$.color')", column)
+
+	column, _, err = columnExpression(MySQL, jsonGrid{}, "color.shade")
+	require.Nil(t, err)
+	assert.Equal(t, "JSON_VALUE(`p`.`attrs`, '$.color.shade')", column)
+
+	column, safe, err = columnExpression(MySQL, jsonGrid{}, "attrs")
+	require.Nil(t, err)
+	assert.True(t, safe)
+	assert.Equal(t, "p.attrs", column)
+
+	assert.True(t, hasTag(jsonGrid{}, "attrs.color", filterable))
+	assert.False(t, hasTag(jsonGrid{}, "id.color", filterable))
+
+	_, _, err = columnExpression(MySQL, jsonGrid{}, "attrs.color') OR 1=1 -- ")
+	assert.EqualError(t, err, "invalid JSON path [$.color') OR 1=1 -- ] of field [attrs.color') OR 1=1 -- ]")
+
+	sql, _, err := createSelects(jsonGrid{}, MySQL, "", nil).ToSql()
+	require.Nil(t, err)
+	assert.Equal(t, "SELECT `p`.`id` as `p.id`, JSON_VALUE(`p`.`attrs`, '$.color') as `p.attrs` FROM product p", sql)
+}
@@ -0,0 +1,160 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+type RelationLoader struct {
+	// Slice field of grid item children are assigned to
+	Field string
+	// Field of grid item holding parent key
+	Key string
+	// Column of child referencing parent key, child struct must map it by its db tag
+	ForeignKey string
+	// Forms FROM (and JOINs) of children query
+	Query func(qb squirrel.SelectBuilder) squirrel.SelectBuilder
+}
+
+/// Relations loaded by single query per relation after the page is loaded
+type RelationLoaders interface {
+	RelationLoaders() []RelationLoader
+}
+
+func loadRelations(ctx context.Context, model Grid, d Dialect, db Executor, items interface{}, selected map[string]bool) error {
+	rl, ok := interface{}(model).(RelationLoaders)
+	if !ok {
+		return nil
+	}
+
+	rows := reflect.Indirect(reflect.ValueOf(items))
+	if rows.Kind() != reflect.Slice || rows.Len() == 0 {
+		return nil
+	}
+
+	// Items may be scanned into other struct than the grid, children are typed by its field
+	row := rows.Type().Elem()
+	if row.Kind() == reflect.Ptr {
+		row = row.Elem()
+	}
+
+	for _, loader := range rl.RelationLoaders() {
+		if selected != nil && !selected[loader.Field] {
+			continue
+		}
+		if loader.Query == nil {
+			return fmt.Errorf("relation [%s] requires query", loader.Field)
+		}
+
+		field, ok := row.FieldByName(loader.Field)
+		if !ok || field.Type.Kind() != reflect.Slice {
+			return fmt.Errorf("relation field [%s] must be a slice", loader.Field)
+		}
+
+		var keys []interface{}
+		seen := map[string]bool{}
+		for i := 0; i < rows.Len(); i++ {
+			key := reflect.Indirect(rows.Index(i)).FieldByName(loader.Key)
+			if !key.IsValid() {
+				return fmt.Errorf("relation key [%s] is not a field", loader.Key)
+			}
+			if !reflect.Indirect(key).IsValid() {
+				continue
+			}
+
+			if !seen[keyOf(key)] {
+				seen[keyOf(key)] = true
+				keys = append(keys, reflect.Indirect(key).Interface())
+			}
+		}
+
+		columns := childColumns(d, field.Type.Elem())
+		sql, args, err := loader.Query(squirrel.Select(columns...).PlaceholderFormat(d.placeholder())).
+			Where(squirrel.Eq{loader.ForeignKey: keys}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		children := reflect.New(field.Type)
+		if err = sqlx.SelectContext(ctx, db, children.Interface(), sql, args...); err != nil {
+			return err
+		}
+
+		if err = assignRelation(items, loader, children.Elem()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Groups children by their foreign key and assigns them to items of matching key
+func assignRelation(items interface{}, loader RelationLoader, children reflect.Value) error {
+	child := children.Type().Elem()
+	if child.Kind() == reflect.Ptr {
+		child = child.Elem()
+	}
+
+	var foreignKey []int
+	for i := 0; i < child.NumField(); i++ {
+		if child.Field(i).Tag.Get("db") == loader.ForeignKey {
+			foreignKey = child.Field(i).Index
+		}
+	}
+	if foreignKey == nil {
+		return fmt.Errorf("relation [%s] requires field mapped to [%s]", loader.Field, loader.ForeignKey)
+	}
+
+	groups := map[string]reflect.Value{}
+	for i := 0; i < children.Len(); i++ {
+		key := keyOf(reflect.Indirect(children.Index(i)).FieldByIndex(foreignKey))
+		if _, ok := groups[key]; !ok {
+			groups[key] = reflect.MakeSlice(children.Type(), 0, 1)
+		}
+		groups[key] = reflect.Append(groups[key], children.Index(i))
+	}
+
+	rows := reflect.Indirect(reflect.ValueOf(items))
+	for i := 0; i < rows.Len(); i++ {
+		row := reflect.Indirect(rows.Index(i))
+		group, ok := groups[keyOf(row.FieldByName(loader.Key))]
+		if !ok {
+			group = reflect.MakeSlice(children.Type(), 0, 0)
+		}
+
+		row.FieldByName(loader.Field).Set(group)
+	}
+
+	return nil
+}
+
+func childColumns(d Dialect, child reflect.Type) []string {
+	if child.Kind() == reflect.Ptr {
+		child = child.Elem()
+	}
+
+	var columns []string
+	for i := 0; i < child.NumField(); i++ {
+		name := child.Field(i).Tag.Get("db")
+		if name != "" && name != "-" {
+			columns = append(columns, fmt.Sprintf("%s as %s", d.Name(name, true), d.quote(name)))
+		}
+	}
+
+	return columns
+}
+
+// Keys of differently typed columns (int vs int64, nullable) are compared by their printed value
+func keyOf(value reflect.Value) string {
+	value = reflect.Indirect(value)
+	if !value.IsValid() {
+		return ""
+	}
+
+	return fmt.Sprint(value.Interface())
+}
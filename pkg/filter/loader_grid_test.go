@@ -0,0 +1,120 @@
+package filter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type loaderTag struct {
+	Id     int    `db:"t.id"`
+	FileId int    `db:"t.file_id"`
+	Name   string `db:"t.Name"`
+}
+
+type loaderGrid struct {
+	Id   int         `db:"f.id" grid:"sort"`
+	Tags []loaderTag `grid:"skip"`
+}
+
+func (T loaderGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("file as f")
+}
+
+func (T loaderGrid) RelationLoaders() []RelationLoader {
+	return []RelationLoader{
+		{
+			Field:      "Tags",
+			Key:        "Id",
+			ForeignKey: "t.file_id",
+			Query: func(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+				return qb.From("tag t").OrderBy("t.id")
+			},
+		},
+	}
+}
+
+func Test_AssignRelation(t *testing.T) {
+	items := []loaderGrid{{Id: 1}, {Id: 2}}
+	tags := []loaderTag{{Id: 1, FileId: 1, Name: "Losos"}, {Id: 2, FileId: 1, Name: "22"}}
+
+	require.Nil(t, assignRelation(&items, loaderGrid{}.RelationLoaders()[0], reflect.ValueOf(tags)))
+	assert.Equal(t, []loaderGrid{{Id: 1, Tags: tags}, {Id: 2, Tags: []loaderTag{}}}, items)
+
+	loader := loaderGrid{}.RelationLoaders()[0]
+	loader.ForeignKey = "t.entity_id"
+	assert.EqualError(
+		t,
+		assignRelation(&items, loader, reflect.ValueOf(tags)),
+		"relation [Tags] requires field mapped to [t.entity_id]",
+	)
+}
+
+type nilQueryGrid struct {
+	Id   int         `db:"f.id"`
+	Tags []loaderTag `grid:"skip"`
+}
+
+func (T nilQueryGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("file as f")
+}
+
+func (T nilQueryGrid) RelationLoaders() []RelationLoader {
+	return []RelationLoader{{Field: "Tags", Key: "Id", ForeignKey: "t.file_id"}}
+}
+
+func Test_LoadRelationsWithoutQuery(t *testing.T) {
+	items := []nilQueryGrid{{Id: 1}}
+	assert.EqualError(
+		t,
+		loadRelations(context.Background(), nilQueryGrid{}, MySQL, nil, &items, nil),
+		"relation [Tags] requires query",
+	)
+}
+
+type loaderTagName struct {
+	FileId int    `db:"t.file_id"`
+	Name   string `db:"t.Name"`
+}
+
+type loaderRow struct {
+	Id   int             `db:"f.id"`
+	Tags []loaderTagName `db:"-"`
+}
+
+func Test_LoaderGrid(t *testing.T) {
+	prepareTestData(t)
+
+	dto := GridDto{
+		Filter: [][]Filter{},
+		Sorter: []Sorter{
+			{
+				Column:    "id",
+				Direction: "ASC",
+			},
+		},
+		Paging: Paging{},
+		Search: "",
+		Items:  nil,
+	}
+
+	var res []loaderGrid
+	dto, err := GetData(loaderGrid{}, dto, MariaDB, &res)
+	require.Nil(t, err)
+
+	assert.Equal(t, 2, dto.Paging.Total)
+	assert.Equal(t, []loaderTag{{Id: 1, FileId: 1, Name: "Losos"}, {Id: 2, FileId: 1, Name: "22"}}, res[0].Tags)
+	assert.Equal(t, []loaderTag{}, res[1].Tags)
+
+	// Rows scanned into other struct get children of its own type
+	var rows []loaderRow
+	_, err = GetData(loaderGrid{}, dto, MariaDB, &rows)
+	require.Nil(t, err)
+
+	assert.Equal(t, []loaderTagName{{FileId: 1, Name: "Losos"}, {FileId: 1, Name: "22"}}, rows[0].Tags)
+	assert.Equal(t, []loaderTagName{}, rows[1].Tags)
+}
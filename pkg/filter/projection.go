@@ -0,0 +1,82 @@
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Struct fields selected by `_fields`, nil selects all of them
+// Unique key and keys of relation loaders are always selected
+func projection(model Grid, fields []string) (map[string]bool, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	loaders := map[string]RelationLoader{}
+	if rl, ok := interface{}(model).(RelationLoaders); ok {
+		for _, loader := range rl.RelationLoaders() {
+			loaders[loader.Field] = loader
+		}
+	}
+
+	fType := reflect.TypeOf(model)
+	selected := map[string]bool{}
+	for _, column := range fields {
+		field, ok := fType.FieldByName(strings.Title(column))
+		if !ok {
+			return nil, fmt.Errorf("field [%s] does not exist", column)
+		}
+
+		if loader, ok := loaders[field.Name]; ok {
+			selected[field.Name] = true
+			selected[loader.Key] = true
+
+			continue
+		}
+
+		_, _, skipped := fieldTag(field, skip)
+		_, _, related := fieldTag(field, relation)
+		if skipped || related {
+			return nil, fmt.Errorf("field [%s] is not selectable", column)
+		}
+
+		selected[field.Name] = true
+	}
+
+	if key := primaryKey(model); key != "" {
+		selected[key] = true
+	}
+
+	return selected, nil
+}
+
+// Rows reduced to selected fields keyed by their json names
+func projectItems(items interface{}, selected map[string]bool) []map[string]interface{} {
+	rows := reflect.Indirect(reflect.ValueOf(items))
+	projected := make([]map[string]interface{}, 0, rows.Len())
+	for i := 0; i < rows.Len(); i++ {
+		row := reflect.Indirect(rows.Index(i))
+		item := map[string]interface{}{}
+		for j := 0; j < row.NumField(); j++ {
+			field := row.Type().Field(j)
+			if !selected[field.Name] {
+				continue
+			}
+
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			item[name] = row.Field(j).Interface()
+		}
+
+		projected = append(projected, item)
+	}
+
+	return projected
+}
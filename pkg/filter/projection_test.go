@@ -0,0 +1,70 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type projectionGrid struct {
+	Id     int         `db:"e.id" json:"id" grid:"sort"`
+	Name   string      `db:"e.name" json:"name" grid:"sort"`
+	Note   string      `db:"e.note" json:"note"`
+	Secret string      `db:"e.secret" json:"-"`
+	Hidden string      `db:"e.hidden" grid:"skip"`
+	Tags   []loaderTag `json:"tags" grid:"skip"`
+}
+
+func (T projectionGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("entity e")
+}
+
+func (T projectionGrid) RelationLoaders() []RelationLoader {
+	return []RelationLoader{
+		{
+			Field:      "Tags",
+			Key:        "Id",
+			ForeignKey: "t.entity_id",
+			Query: func(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+				return qb.From("tag t")
+			},
+		},
+	}
+}
+
+func Test_Projection(t *testing.T) {
+	selected, err := projection(projectionGrid{}, nil)
+	require.Nil(t, err)
+	assert.Nil(t, selected)
+
+	selected, err = projection(projectionGrid{}, []string{"name"})
+	require.Nil(t, err)
+	assert.Equal(t, map[string]bool{"Id": true, "Name": true}, selected)
+
+	sql, _, err := createSelects(projectionGrid{}, MySQL, "", selected).ToSql()
+	require.Nil(t, err)
+	assert.Equal(t, "SELECT `e`.`id` as `e.id`, `e`.`name` as `e.name` FROM entity e", sql)
+
+	selected, err = projection(projectionGrid{}, []string{"tags"})
+	require.Nil(t, err)
+	assert.Equal(t, map[string]bool{"Id": true, "Tags": true}, selected)
+
+	_, err = projection(projectionGrid{}, []string{"name", "hidden"})
+	assert.EqualError(t, err, "field [hidden] is not selectable")
+
+	_, err = projection(projectionGrid{}, []string{"e.name"})
+	assert.EqualError(t, err, "field [e.name] does not exist")
+}
+
+func Test_ProjectItems(t *testing.T) {
+	items := []projectionGrid{{Id: 1, Name: "Losos", Note: "long", Secret: "s"}}
+
+	assert.Equal(
+		t,
+		[]map[string]interface{}{{"id": 1, "name": "Losos"}},
+		projectItems(&items, map[string]bool{"Id": true, "Name": true, "Secret": true}),
+	)
+	assert.Equal(t, []map[string]interface{}{}, projectItems([]projectionGrid{}, map[string]bool{"Id": true}))
+}
@@ -0,0 +1,110 @@
+package filter
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type QueryMode int
+
+const (
+	// Count and page queries run one after another
+	Sequential QueryMode = iota
+	// Count and page queries run at once on separate connections of the pool
+	Concurrent
+	// All queries run within single read-only REPEATABLE READ transaction
+	Snapshot
+)
+
+// Runs grid queries, satisfied by *sqlx.DB and *sqlx.Tx
+// Queries given transaction run within it, query modes apply only to *sqlx.DB
+type Executor interface {
+	sqlx.QueryerContext
+	DriverName() string
+}
+
+// Executors of connection pool able to start transaction
+type txBeginner interface {
+	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
+}
+
+/// Grids running count and page queries other way than sequentially
+type QueryModes interface {
+	QueryMode() QueryMode
+}
+
+type gridQuery func(ctx context.Context, db Executor) error
+
+func queryMode(model Grid) QueryMode {
+	if qm, ok := interface{}(model).(QueryModes); ok {
+		return qm.QueryMode()
+	}
+
+	return Sequential
+}
+
+// Transaction runs single query at once, so queries within it run sequentially
+func runQueries(ctx context.Context, mode QueryMode, db Executor, queries ...gridQuery) error {
+	if _, pool := db.(txBeginner); pool && mode == Concurrent {
+		return runConcurrently(ctx, db, queries)
+	}
+
+	return runSequentially(ctx, db, queries)
+}
+
+// Snapshot mode runs all queries within read-only REPEATABLE READ transaction
+// InnoDB takes the snapshot by the first read, PostgreSQL by the first statement of transaction
+func inSnapshot(ctx context.Context, mode QueryMode, db Executor, run func(db Executor) error) error {
+	beginner, pool := db.(txBeginner)
+	if mode != Snapshot || !pool {
+		return run(db)
+	}
+
+	tx, err := beginner.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+
+	if err = run(tx); err != nil {
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func runSequentially(ctx context.Context, db Executor, queries []gridQuery) error {
+	for _, query := range queries {
+		if err := query(ctx, db); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// First error cancels the other queries and is returned
+func runConcurrently(ctx context.Context, db Executor, queries []gridQuery) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, len(queries))
+	for _, query := range queries {
+		go func(query gridQuery) {
+			errs <- query(ctx, db)
+		}(query)
+	}
+
+	var first error
+	for range queries {
+		if err := <-errs; err != nil && first == nil {
+			first = err
+			cancel()
+		}
+	}
+
+	return first
+}
@@ -0,0 +1,123 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type concurrentGrid struct {
+	Id int `db:"t.id" grid:"filter,sort"`
+}
+
+func (T concurrentGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("tag as t")
+}
+
+func (T concurrentGrid) QueryMode() QueryMode {
+	return Concurrent
+}
+
+type snapshotGrid struct {
+	Id int `db:"t.id" grid:"filter,sort"`
+}
+
+func (T snapshotGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("tag as t")
+}
+
+func (T snapshotGrid) QueryMode() QueryMode {
+	return Snapshot
+}
+
+func Test_RunConcurrently(t *testing.T) {
+	failed := errors.New("count failed")
+	canceled := false
+
+	err := runConcurrently(context.Background(), nil, []gridQuery{
+		func(ctx context.Context, db Executor) error {
+			return failed
+		},
+		func(ctx context.Context, db Executor) error {
+			<-ctx.Done()
+			canceled = true
+
+			return ctx.Err()
+		},
+	})
+	assert.Equal(t, failed, err)
+	assert.True(t, canceled)
+
+	ran := 0
+	err = runSequentially(context.Background(), nil, []gridQuery{
+		func(ctx context.Context, db Executor) error {
+			ran++
+
+			return failed
+		},
+		func(ctx context.Context, db Executor) error {
+			ran++
+
+			return nil
+		},
+	})
+	assert.Equal(t, failed, err)
+	assert.Equal(t, 1, ran)
+
+	assert.Equal(t, Sequential, queryMode(loaderGrid{}))
+	assert.Equal(t, Snapshot, queryMode(snapshotGrid{}))
+
+	// Transaction of caller is used as it is
+	ran = 0
+	err = inSnapshot(context.Background(), Snapshot, nil, func(db Executor) error {
+		ran++
+		assert.Nil(t, db)
+
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, ran)
+}
+
+func Test_QueryModeGrid(t *testing.T) {
+	prepareTestData(t)
+
+	var res []concurrentGrid
+	dto, err := GetData(concurrentGrid{}, GridDto{Sorter: []Sorter{{Column: "id", Direction: Desc}}}, MariaDB, &res)
+	require.Nil(t, err)
+
+	assert.Equal(t, 2, dto.Paging.Total)
+	assert.Equal(t, []concurrentGrid{{Id: 2}, {Id: 1}}, res)
+
+	var snapshot []snapshotGrid
+	dto, err = GetData(snapshotGrid{}, GridDto{}, MariaDB, &snapshot)
+	require.Nil(t, err)
+
+	assert.Equal(t, 2, dto.Paging.Total)
+	assert.Len(t, snapshot, 2)
+
+	tx, err := MariaDB.Beginx()
+	require.Nil(t, err)
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.Exec("INSERT INTO tag VALUES (3, 2, 'Kapr')")
+	require.Nil(t, err)
+
+	snapshot = nil
+	dto, err = GetData(snapshotGrid{}, GridDto{}, tx, &snapshot)
+	require.Nil(t, err)
+
+	assert.Equal(t, 3, dto.Paging.Total)
+	assert.Len(t, snapshot, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = GetDataContext(ctx, concurrentGrid{}, GridDto{}, MariaDB, &res)
+	assert.Equal(t, context.Canceled, err)
+}
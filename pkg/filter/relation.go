@@ -0,0 +1,65 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const relation = "relation"
+
+// Negative operators and their positive counterparts
+var negatedOperators = map[string]string{
+	Neq:      Eq,
+	Nin:      In,
+	Nlike:    Like,
+	Nbetween: Between,
+	Nregex:   Regex,
+	Empty:    Nempty,
+}
+
+// Related columns are not joined within main query, so they can't be sorted, counted, aggregated or grouped by
+func checkSelectable(model Grid, column string) error {
+	if hasTag(model, column, relation) {
+		return fmt.Errorf("field [%s] is not selectable", column)
+	}
+
+	return nil
+}
+
+// Moves condition on column of related table into EXISTS subquery (`relation=table:join condition`)
+// Negated subquery (NOT EXISTS) matches rows without any such related row
+func relationQuery(model Grid, column string, query squirrel.Sqlizer, negate bool) (squirrel.Sqlizer, error) {
+	declared, ok := tagValue(model, column, relation)
+	if !ok {
+		return query, nil
+	}
+
+	parts := strings.SplitN(declared, ":", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return nil, fmt.Errorf("invalid relation [%s] of field [%s]", declared, column)
+	}
+
+	table := strings.TrimSpace(parts[0])
+	if !strings.Contains(table, " ") {
+		if name := taggedName(model, column); strings.Contains(name, ".") {
+			table = fmt.Sprintf("%s %s", table, strings.SplitN(name, ".", 2)[0])
+		}
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	exists := "EXISTS"
+	if negate {
+		exists = "NOT EXISTS"
+	}
+
+	return squirrel.Expr(
+		fmt.Sprintf("%s (SELECT 1 FROM %s WHERE %s AND (%s))", exists, table, strings.TrimSpace(parts[1]), sql),
+		args...,
+	), nil
+}
@@ -0,0 +1,130 @@
+package filter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type relationGrid struct {
+	Id        int       `db:"f.id" grid:"filter"`
+	TagName   string    `db:"t.Name" grid:"filter,search,relation=tag:t.file_id = f.id"`
+	TaggedAt  time.Time `db:"t.created" grid:"sort,facet,group,relation=tag:t.file_id = f.id"`
+	TagWeight int       `db:"t.weight" grid:"footer,relation=tag:t.file_id = f.id"`
+}
+
+func (T relationGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("file as f")
+}
+
+func Test_RelationQuery(t *testing.T) {
+	sql, args := sqlOf(t)(filterQuery(MySQL, relationGrid{}, Filter{Column: "tagName", Operator: Eq, Value: []string{"Losos"}}))
+	assert.Equal(t, "EXISTS (SELECT 1 FROM tag t WHERE t.file_id = f.id AND (`t`.`Name` = ?))", sql)
+	assert.Equal(t, []interface{}{"Losos"}, args)
+
+	sql, args = sqlOf(t)(filterQuery(MySQL, relationGrid{}, Filter{Column: "tagName", Operator: Neq, Value: []string{"Losos"}}))
+	assert.Equal(t, "NOT EXISTS (SELECT 1 FROM tag t WHERE t.file_id = f.id AND (`t`.`Name` = ?))", sql)
+	assert.Equal(t, []interface{}{"Losos"}, args)
+
+	sql, args = sqlOf(t)(filterQuery(MySQL, relationGrid{}, Filter{Column: "tagName", Operator: Nin, Value: []string{"Losos", "Pstruh"}}))
+	assert.Equal(t, "NOT EXISTS (SELECT 1 FROM tag t WHERE t.file_id = f.id AND (`t`.`Name` IN (?,?)))", sql)
+	assert.Equal(t, []interface{}{"Losos", "Pstruh"}, args)
+
+	sql, _ = sqlOf(t)(filterQuery(MySQL, relationGrid{}, Filter{Column: "tagName", Operator: Empty, Value: []string{"1"}}))
+	assert.Equal(t, "NOT EXISTS (SELECT 1 FROM tag t WHERE t.file_id = f.id AND (`t`.`Name` IS NOT NULL))", sql)
+
+	sql, args = sqlOf(t)(searchQuery(MySQL, relationGrid{}, tokenize("los -pstruh")))
+	assert.Equal(
+		t,
+		"((EXISTS (SELECT 1 FROM tag t WHERE t.file_id = f.id AND (`t`.`Name` LIKE ?))) AND "+
+			"NOT EXISTS (SELECT 1 FROM tag t WHERE t.file_id = f.id AND (`t`.`Name` LIKE ?)))",
+		sql,
+	)
+	assert.Equal(t, []interface{}{"%los%", "%pstruh%"}, args)
+
+	sql, _, err := createSelects(relationGrid{}, MySQL, "", nil).ToSql()
+	require.Nil(t, err)
+	assert.Equal(t, "SELECT `f`.`id` as `f.id` FROM file as f", sql)
+}
+
+func Test_RelationNotSelectable(t *testing.T) {
+	_, err := orderBy(MySQL, relationGrid{}, []Sorter{{Column: "taggedAt"}}, nil, squirrel.Select("*"))
+	assert.EqualError(t, err, "field [taggedAt] is not selectable")
+
+	_, err = facets(context.Background(), MySQL, relationGrid{}, nil, GridDto{Facet: []string{"taggedAt"}}, Location, nil)
+	assert.EqualError(t, err, "field [taggedAt] is not selectable")
+
+	err = checkAggregate(relationGrid{}, Aggregate{Column: "tagWeight", Function: Sum})
+	assert.EqualError(t, err, "field [tagWeight] is not selectable")
+
+	_, err = groupKeys(MySQL, relationGrid{}, GridDto{Group: []string{"taggedAt"}}, Location)
+	assert.EqualError(t, err, "field [taggedAt] is not selectable")
+
+	_, err = groupKeys(MySQL, relationGrid{}, GridDto{Bucket: &Bucket{Column: "taggedAt", Unit: Day}}, Location)
+	assert.EqualError(t, err, "field [taggedAt] is not selectable")
+}
+
+func Test_RelationGrid(t *testing.T) {
+	prepareTestData(t)
+
+	dto := GridDto{
+		Filter: [][]Filter{
+			{
+				{
+					Column:   "tagName",
+					Operator: "EQ",
+					Value:    []string{"Losos"},
+				},
+			},
+		},
+		Sorter: nil,
+		Paging: Paging{},
+		Search: "",
+		Items:  nil,
+	}
+
+	var res []relationGrid
+	dto, err := GetData(relationGrid{}, dto, MariaDB, &res)
+	require.Nil(t, err)
+
+	assert.Equal(t, 1, dto.Paging.Total)
+	assert.Equal(t, 1, res[0].Id)
+
+	dto = GridDto{
+		Filter: [][]Filter{
+			{
+				{
+					Column:   "tagName",
+					Operator: "NEMPTY",
+					Value:    []string{"1"},
+				},
+			},
+		},
+		Sorter: nil,
+		Paging: Paging{},
+		Search: "",
+		Items:  nil,
+	}
+
+	res = make([]relationGrid, 0)
+	dto, err = GetData(relationGrid{}, dto, MariaDB, &res)
+	require.Nil(t, err)
+
+	assert.Equal(t, 1, dto.Paging.Total)
+
+	// File without tags has none of them
+	for _, operator := range []string{Neq, Nin, Empty} {
+		dto = GridDto{Filter: [][]Filter{{{Column: "tagName", Operator: operator, Value: []string{"Losos"}}}}}
+
+		res = make([]relationGrid, 0)
+		dto, err = GetData(relationGrid{}, dto, MariaDB, &res)
+		require.Nil(t, err)
+
+		assert.Equal(t, 1, dto.Paging.Total, operator)
+		assert.Equal(t, 2, res[0].Id, operator)
+	}
+}
@@ -12,15 +12,36 @@ const (
 	size        = "_size"
 	sorter      = "_sorter"
 	filter      = "_filter"
+	highlight   = "_highlight"
+	timezone    = "_tz"
+	fields      = "_fields"
+	facet       = "_facet"
+	aggregates  = "_aggregate"
+	group       = "_group"
+	bucket      = "_bucket"
+	countTotal  = "_count"
 	defaultSize = 10
+
+	timezoneHeader = "X-Timezone"
 )
 
 type GridDto struct {
-	Filter [][]Filter  `json:"filter"`
-	Sorter []Sorter    `json:"sorter"`
-	Paging Paging      `json:"paging"`
-	Search string      `json:"search"`
-	Items  interface{} `json:"items"`
+	Filter     [][]Filter      `json:"filter"`
+	Sorter     []Sorter        `json:"sorter"`
+	Paging     Paging          `json:"paging"`
+	Aggregates AggregateValues `json:"aggregates,omitempty"`
+	Search     string          `json:"search"`
+	Highlight  bool            `json:"highlight,omitempty"`
+	Timezone   string          `json:"timezone,omitempty"`
+	Fields     []string        `json:"fields,omitempty"`
+	Facet      []string        `json:"facet,omitempty"`
+	Aggregate  []Aggregate     `json:"aggregate,omitempty"`
+	Group      []string        `json:"group,omitempty"`
+	Bucket     *Bucket         `json:"bucket,omitempty"`
+	SkipCount  bool            `json:"skipCount,omitempty"`
+	Items      interface{}     `json:"items"`
+	Highlights []Highlight     `json:"highlights,omitempty"`
+	Facets     []Facet         `json:"facets,omitempty"`
 }
 
 type Filter struct {
@@ -35,12 +56,14 @@ type Sorter struct {
 }
 
 type Paging struct {
-	Page         int `json:"page"`
-	Size         int `json:"size"`
-	LastPage     int `json:"lastPage"`
-	NextPage     int `json:"nextPage"`
-	PreviousPage int `json:"previousPage"`
-	Total        int `json:"total"`
+	Page         int  `json:"page"`
+	Size         int  `json:"size"`
+	LastPage     int  `json:"lastPage"`
+	NextPage     int  `json:"nextPage"`
+	PreviousPage int  `json:"previousPage"`
+	Total        int  `json:"total"`
+	HasMore      bool `json:"hasMore"`
+	Estimated    bool `json:"estimated,omitempty"`
 }
 
 func CreateGridDto(request *http.Request) GridDto {
@@ -51,6 +74,7 @@ func CreateGridDto(request *http.Request) GridDto {
 			Size: defaultSize,
 			Page: 1,
 		},
+		Timezone: request.Header.Get(timezoneHeader),
 	}
 
 	values := request.URL.Query()
@@ -61,6 +85,67 @@ func CreateGridDto(request *http.Request) GridDto {
 			continue
 		}
 
+		if key == timezone {
+			dto.Timezone = values.Get(key)
+			continue
+		}
+
+		if key == fields {
+			dto.Fields = listVal(values.Get(key))
+			continue
+		}
+
+		if key == facet {
+			dto.Facet = listVal(values.Get(key))
+			continue
+		}
+
+		// _aggregate=column:function,column2:function, items without function are rejected by grid
+		if key == aggregates {
+			for _, item := range listVal(values.Get(key)) {
+				parts := strings.SplitN(item, ":", 2)
+				function := ""
+				if len(parts) == 2 {
+					function = strings.ToLower(parts[1])
+				}
+
+				dto.Aggregate = append(dto.Aggregate, Aggregate{
+					Column:   parts[0],
+					Function: function,
+				})
+			}
+
+			continue
+		}
+
+		if key == group {
+			dto.Group = listVal(values.Get(key))
+			continue
+		}
+
+		// _bucket=column:unit
+		if key == bucket {
+			parts := strings.SplitN(values.Get(key), ":", 2)
+			if len(parts) == 2 {
+				dto.Bucket = &Bucket{Column: parts[0], Unit: strings.ToLower(parts[1])}
+			}
+
+			continue
+		}
+
+		if key == countTotal {
+			if counted, err := strconv.ParseBool(values.Get(key)); err == nil {
+				dto.SkipCount = !counted
+			}
+
+			continue
+		}
+
+		if key == highlight {
+			dto.Highlight = boolVal(values.Get(key))
+			continue
+		}
+
 		if key == page {
 			dto.Paging.Page = intVal(values.Get(page))
 			if dto.Paging.Page <= 0 {
@@ -117,10 +202,16 @@ func CreateGridDto(request *http.Request) GridDto {
 
 			dto = extendFilter(dto, index)
 
+			// Patterns may contain commas themselves ({2,3})
+			value := strings.Split(values.Get(key), ",")
+			if operator == Regex || operator == Nregex {
+				value = []string{values.Get(key)}
+			}
+
 			dto.Filter[index] = append(dto.Filter[index], Filter{
 				Column:   column,
 				Operator: operator,
-				Value:    strings.Split(values.Get(key), ","),
+				Value:    value,
 			})
 		}
 	}
@@ -137,6 +228,27 @@ func intVal(value string) int {
 	return val
 }
 
+func boolVal(value string) bool {
+	val, err := strconv.ParseBool(value)
+	if err != nil {
+		return false
+	}
+
+	return val
+}
+
+// Comma separated values, empty ones are left out
+func listVal(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
 func extendSorter(dto GridDto, length int) GridDto {
 	if len(dto.Sorter) > length {
 		return dto
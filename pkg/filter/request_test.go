@@ -18,6 +18,8 @@ func TestCreateGridDto(t *testing.T) {
 		"_filter:col2:NEQ:1=a",
 		"_filter:col:EQ=asd,qwe",
 		"_sorter:cc=ASC",
+		"_filter:code:REGEX:2=^[A-Z]{2,3}$",
+		"_tz=Europe/Prague",
 	}
 
 	req, _ := http.NewRequest(
@@ -43,6 +45,13 @@ func TestCreateGridDto(t *testing.T) {
 					Value:    []string{"a"},
 				},
 			},
+			{
+				{
+					Column:   "code",
+					Operator: "REGEX",
+					Value:    []string{"^[A-Z]{2,3}$"},
+				},
+			},
 		},
 		Sorter: []Sorter{
 			{
@@ -54,8 +63,31 @@ func TestCreateGridDto(t *testing.T) {
 			Page: 5,
 			Size: 50,
 		},
-		Search: "search",
+		Search:   "search",
+		Timezone: "Europe/Prague",
 	}
 
 	assert.Equal(t, exp, dto)
+
+	req.URL.RawQuery = ""
+	req.Header.Set("X-Timezone", "America/New_York")
+	assert.Equal(t, "America/New_York", CreateGridDto(req).Timezone)
+
+	req.URL.RawQuery = "_fields=id, name,,status"
+	assert.Equal(t, []string{"id", "name", "status"}, CreateGridDto(req).Fields)
+
+	req.URL.RawQuery = "_facet=status,type"
+	assert.Equal(t, []string{"status", "type"}, CreateGridDto(req).Facet)
+
+	req.URL.RawQuery = "_group=status&_bucket=createdAt:DAY&_aggregate=amount:sum,amount:AVG,price"
+	dto = CreateGridDto(req)
+	assert.Equal(t, []string{"status"}, dto.Group)
+	assert.Equal(t, &Bucket{Column: "createdAt", Unit: Day}, dto.Bucket)
+	assert.Equal(t, []Aggregate{{Column: "amount", Function: Sum}, {Column: "amount", Function: Avg}, {Column: "price"}}, dto.Aggregate)
+
+	req.URL.RawQuery = "_count=false"
+	assert.True(t, CreateGridDto(req).SkipCount)
+
+	req.URL.RawQuery = "_count=maybe"
+	assert.False(t, CreateGridDto(req).SkipCount)
 }
@@ -0,0 +1,286 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type SearchMode string
+
+const (
+	LikeSearch     SearchMode = "like"
+	FulltextSearch SearchMode = "fulltext"
+
+	// Virtual column sorting searched rows by relevance
+	Relevance = "relevance"
+)
+
+/// Switches search of the grid to another mode (FULLTEXT index is required on MySQL/MariaDB)
+type SearchModes interface {
+	SearchMode() SearchMode
+}
+
+type searchToken struct {
+	value   string
+	exclude bool
+}
+
+// Splits search into words, "quoted phrases" are kept whole and -prefixed tokens are excluded
+func tokenize(search string) []searchToken {
+	var tokens []searchToken
+	var current strings.Builder
+	exclude := false
+	quoted := false
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, searchToken{value: current.String(), exclude: exclude})
+		}
+		current.Reset()
+		exclude = false
+	}
+
+	for _, r := range search {
+		switch {
+		case r == '"':
+			if quoted {
+				flush()
+			}
+			quoted = !quoted
+		case quoted:
+			current.WriteRune(r)
+		case unicode.IsSpace(r):
+			flush()
+		case r == '-' && current.Len() == 0:
+			exclude = true
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// Every included token must match at least one searchable field, excluded tokens none of them
+// In fulltext mode the same is expressed by boolean mode MATCH
+func searchQuery(d Dialect, model Grid, tokens []searchToken) (squirrel.Sqlizer, error) {
+	fields := getSearchFields(model)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	if fulltext(d, model) {
+		if err := checkFulltext(model, fields); err != nil {
+			return nil, err
+		}
+		if against := fulltextAgainst(tokens); against != "" {
+			return squirrel.Expr(matchQuery(d, model, fields), against), nil
+		}
+	}
+
+	var andQueries squirrel.And
+	for _, token := range tokens {
+		var orQueries squirrel.Or
+		for _, field := range fields {
+			query, err := tokenQuery(d, model, field, token)
+			if err != nil {
+				return nil, err
+			}
+
+			if token.exclude {
+				andQueries = append(andQueries, query)
+			} else {
+				orQueries = append(orQueries, query)
+			}
+		}
+
+		if orQueries != nil {
+			andQueries = append(andQueries, orQueries)
+		}
+	}
+
+	if andQueries == nil {
+		return nil, nil
+	}
+
+	return andQueries, nil
+}
+
+// Sum of weights of fields matching included tokens or MATCH score in fulltext mode
+func relevanceQuery(d Dialect, model Grid, tokens []searchToken) (squirrel.Sqlizer, error) {
+	fields := getSearchFields(model)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	if fulltext(d, model) {
+		if err := checkFulltext(model, fields); err != nil {
+			return nil, err
+		}
+		if against := fulltextAgainst(tokens); against != "" {
+			return squirrel.Expr(matchQuery(d, model, fields), against), nil
+		}
+
+		return nil, nil
+	}
+
+	var parts []string
+	var args []interface{}
+	for _, token := range tokens {
+		if token.exclude {
+			continue
+		}
+
+		for _, field := range fields {
+			query, err := tokenQuery(d, model, field, token)
+			if err != nil {
+				return nil, err
+			}
+
+			sql, queryArgs, err := query.ToSql()
+			if err != nil {
+				return nil, err
+			}
+
+			parts = append(parts, fmt.Sprintf("CASE WHEN %s THEN %d ELSE 0 END", sql, searchWeight(model, field)))
+			args = append(args, queryArgs...)
+		}
+	}
+
+	if len(parts) == 0 {
+		return nil, nil
+	}
+
+	return squirrel.Expr(fmt.Sprintf("(%s)", strings.Join(parts, " + ")), args...), nil
+}
+
+// Field contains included token or does not contain excluded one
+func tokenQuery(d Dialect, model Grid, field string, token searchToken) (squirrel.Sqlizer, error) {
+	like := d.FormQuery(taggedName(model, field), Like, []string{token.value}, true)
+	if hasTag(model, field, relation) {
+		return relationQuery(model, field, like, token.exclude)
+	}
+
+	if token.exclude {
+		name := d.Name(taggedName(model, field), true)
+
+		return squirrel.Expr(
+			fmt.Sprintf("(%s IS NULL OR %s NOT LIKE ?)", name, name),
+			ParseValues([]string{token.value}, Nlike)...,
+		), nil
+	}
+
+	return like, nil
+}
+
+// Rows are ordered by relevance by default in fulltext mode or when any field declares its weight
+func relevanceOrdered(d Dialect, model Grid) bool {
+	if fulltext(d, model) {
+		return true
+	}
+
+	for _, field := range getSearchFields(model) {
+		if _, ok := tagValue(model, field, searchable); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func searchWeight(model Grid, field string) int {
+	if value, ok := tagValue(model, field, searchable); ok {
+		if weight, err := strconv.Atoi(value); err == nil {
+			return weight
+		}
+	}
+
+	return 1
+}
+
+func fulltext(d Dialect, model Grid) bool {
+	mode, ok := interface{}(model).(SearchModes)
+
+	return ok && d == MySQL && mode.SearchMode() == FulltextSearch
+}
+
+// Related table is not joined within main query, so its columns can't be matched
+func checkFulltext(model Grid, fields []string) error {
+	for _, field := range fields {
+		if hasTag(model, field, relation) {
+			return fmt.Errorf("relation field [%s] can't be searched in fulltext mode", field)
+		}
+	}
+
+	return nil
+}
+
+func matchQuery(d Dialect, model Grid, fields []string) string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = d.Name(taggedName(model, field), true)
+	}
+
+	return fmt.Sprintf("MATCH(%s) AGAINST(? IN BOOLEAN MODE)", strings.Join(names, ", "))
+}
+
+// Forms boolean mode query, empty when there is no included token to match
+// Words joined by - or @ (a-b) are indexed separately, so they are matched as a phrase
+func fulltextAgainst(tokens []searchToken) string {
+	var parts []string
+	included := false
+	for _, token := range tokens {
+		value := strings.Map(func(r rune) rune {
+			if r == '-' || r == '@' {
+				return ' '
+			}
+			if strings.ContainsRune(`+<>()~*"`, r) {
+				return -1
+			}
+
+			return r
+		}, token.value)
+		value = strings.Join(strings.Fields(value), " ")
+		if value == "" {
+			continue
+		}
+
+		if strings.IndexFunc(value, unicode.IsSpace) >= 0 {
+			value = fmt.Sprintf(`"%s"`, value)
+		} else {
+			value = fmt.Sprintf("%s*", value)
+		}
+
+		if token.exclude {
+			parts = append(parts, fmt.Sprintf("-%s", value))
+		} else {
+			included = true
+			parts = append(parts, fmt.Sprintf("+%s", value))
+		}
+	}
+
+	if !included {
+		return ""
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func orderByRelevance(d Dialect, model Grid, tokens []searchToken, qb squirrel.SelectBuilder, direction string) (squirrel.SelectBuilder, error) {
+	relevance, err := relevanceQuery(d, model, tokens)
+	if err != nil || relevance == nil {
+		return qb, err
+	}
+
+	sql, args, err := relevance.ToSql()
+	if err != nil {
+		return qb, err
+	}
+
+	return qb.OrderByClause(fmt.Sprintf("%s %s", sql, direction), args...), nil
+}
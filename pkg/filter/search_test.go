@@ -0,0 +1,130 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type searchGrid struct {
+	Id      int    `db:"p.id"`
+	Name    string `db:"p.name" grid:"search=3"`
+	Surname string `db:"p.surname" grid:"search"`
+}
+
+func (T searchGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("person p")
+}
+
+func sqlOf(t *testing.T) func(squirrel.Sqlizer, error) (string, []interface{}) {
+	return func(query squirrel.Sqlizer, err error) (string, []interface{}) {
+		require.Nil(t, err)
+		require.NotNil(t, query)
+
+		sql, args, err := query.ToSql()
+		require.Nil(t, err)
+
+		return sql, args
+	}
+}
+
+func Test_Tokenize(t *testing.T) {
+	assert.Equal(t, []searchToken{
+		{value: "john"},
+		{value: "smith"},
+	}, tokenize("  john   smith "))
+
+	assert.Equal(t, []searchToken{
+		{value: "john smith"},
+		{value: "jr", exclude: true},
+		{value: "old town", exclude: true},
+		{value: "a-b"},
+	}, tokenize(`"john smith" -jr -"old town" a-b -`))
+
+	assert.Nil(t, tokenize(""))
+}
+
+func Test_SearchQuery(t *testing.T) {
+	sql, args := sqlOf(t)(searchQuery(MySQL, searchGrid{}, tokenize("john -smith")))
+	assert.Equal(
+		t,
+		"((`p`.`name` LIKE ? OR `p`.`surname` LIKE ?) AND "+
+			"(`p`.`name` IS NULL OR `p`.`name` NOT LIKE ?) AND "+
+			"(`p`.`surname` IS NULL OR `p`.`surname` NOT LIKE ?))",
+		sql,
+	)
+	assert.Equal(t, []interface{}{"%john%", "%john%", "%smith%", "%smith%"}, args)
+
+	sql, args = sqlOf(t)(relevanceQuery(MySQL, searchGrid{}, tokenize("john -smith")))
+	assert.Equal(
+		t,
+		"(CASE WHEN `p`.`name` LIKE ? THEN 3 ELSE 0 END + CASE WHEN `p`.`surname` LIKE ? THEN 1 ELSE 0 END)",
+		sql,
+	)
+	assert.Equal(t, []interface{}{"%john%", "%john%"}, args)
+
+	query, err := relevanceQuery(MySQL, singleTableGrid{}, tokenize("john"))
+	require.Nil(t, err)
+	assert.Nil(t, query)
+	query, err = searchQuery(MySQL, singleTableGrid{}, tokenize("john"))
+	require.Nil(t, err)
+	assert.Nil(t, query)
+}
+
+type fulltextGrid struct {
+	Id   int    `db:"a.id"`
+	Name string `db:"a.name" grid:"search"`
+	Text string `db:"a.text" grid:"search"`
+}
+
+func (T fulltextGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("article a")
+}
+
+func (T fulltextGrid) SearchMode() SearchMode {
+	return FulltextSearch
+}
+
+type fulltextRelationGrid struct {
+	Id      int    `db:"a.id"`
+	Name    string `db:"a.name" grid:"search"`
+	TagName string `db:"t.name" grid:"search,relation=tag:t.article_id = a.id"`
+}
+
+func (T fulltextRelationGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("article a")
+}
+
+func (T fulltextRelationGrid) SearchMode() SearchMode {
+	return FulltextSearch
+}
+
+func Test_FulltextSearchQuery(t *testing.T) {
+	assert.Equal(t, `+john* +"old town" -jr*`, fulltextAgainst(tokenize(`jo(hn) "old town" -jr`)))
+	assert.Equal(t, "", fulltextAgainst(tokenize(`-jr ***`)))
+	assert.Equal(t, `+"a b" -"jr sr" +"john doe"`, fulltextAgainst(tokenize(`a-b -jr-sr john@doe`)))
+
+	sql, args := sqlOf(t)(searchQuery(MySQL, fulltextGrid{}, tokenize("john -smith")))
+	assert.Equal(t, "MATCH(`a`.`name`, `a`.`text`) AGAINST(? IN BOOLEAN MODE)", sql)
+	assert.Equal(t, []interface{}{"+john* -smith*"}, args)
+
+	sql, args = sqlOf(t)(relevanceQuery(MySQL, fulltextGrid{}, tokenize("john")))
+	assert.Equal(t, "MATCH(`a`.`name`, `a`.`text`) AGAINST(? IN BOOLEAN MODE)", sql)
+	assert.Equal(t, []interface{}{"+john*"}, args)
+	assert.True(t, relevanceOrdered(MySQL, fulltextGrid{}))
+
+	// Only exclusions and other dialects fall back to LIKE
+	sql, _ = sqlOf(t)(searchQuery(MySQL, fulltextGrid{}, tokenize("-smith")))
+	assert.Equal(t, "((`a`.`name` IS NULL OR `a`.`name` NOT LIKE ?) AND (`a`.`text` IS NULL OR `a`.`text` NOT LIKE ?))", sql)
+
+	sql, _ = sqlOf(t)(searchQuery(PostgreSQL, fulltextGrid{}, tokenize("john")))
+	assert.Equal(t, `(("a"."name" LIKE ? OR "a"."text" LIKE ?))`, sql)
+	assert.False(t, relevanceOrdered(PostgreSQL, fulltextGrid{}))
+
+	_, err := searchQuery(MySQL, fulltextRelationGrid{}, tokenize("john"))
+	assert.EqualError(t, err, "relation field [TagName] can't be searched in fulltext mode")
+	_, err = relevanceQuery(MySQL, fulltextRelationGrid{}, tokenize("john"))
+	assert.EqualError(t, err, "relation field [TagName] can't be searched in fulltext mode")
+}
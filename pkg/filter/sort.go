@@ -0,0 +1,208 @@
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const (
+	Asc  = "ASC"
+	Desc = "DESC"
+
+	NullsFirst = "FIRST"
+	NullsLast  = "LAST"
+
+	nullsOrder = "nulls"
+	uniqueKey  = "key"
+)
+
+type SortCallback func(field, direction string) squirrel.Sqlizer
+
+/// Sorters applied when request defines none
+type DefaultSorters interface {
+	DefaultSorters() []Sorter
+}
+
+/// Callbacks defining ORDER BY expression of sortable field (FIELD(), CASE, ...)
+type SortCallbacks interface {
+	SortCallbacks() map[string]SortCallback
+}
+
+func orderBy(d Dialect, model Grid, sorters []Sorter, tokens []searchToken, qb squirrel.SelectBuilder) (squirrel.SelectBuilder, error) {
+	sortCalls := map[string]SortCallback{}
+	if scl, ok := interface{}(model).(SortCallbacks); ok {
+		sortCalls = scl.SortCallbacks()
+	}
+
+	for _, sorter := range sorters {
+		direction, nulls, err := sortDirection(sorter)
+		if err != nil {
+			return qb, err
+		}
+
+		if err := checkSelectable(model, sorter.Column); err != nil {
+			return qb, err
+		}
+
+		if hasTag(model, sorter.Column, sortable) {
+			if nulls == "" {
+				if nulls, err = nullsPlacement(model, sorter.Column); err != nil {
+					return qb, err
+				}
+			}
+
+			tagName := taggedName(model, sorter.Column)
+			if callback, ok := sortCalls[tagName]; ok {
+				// Expression of callback is not known, so NULLs can't be placed around it
+				if nulls != "" {
+					return qb, fmt.Errorf("field [%s] sorted by callback does not support NULLs placement", sorter.Column)
+				}
+
+				sql, args, err := callback(tagName, direction).ToSql()
+				if err != nil {
+					return qb, err
+				}
+
+				qb = qb.OrderByClause(sql, args...)
+
+				continue
+			}
+
+			column, safe, err := columnExpression(d, model, sorter.Column)
+			if err != nil {
+				return qb, err
+			}
+
+			qb = qb.OrderBy(d.orderBy(d.Name(column, safe), direction, nulls)...)
+		} else if sorter.Column == Relevance {
+			// Relevance is never NULL
+			if nulls != "" {
+				return qb, fmt.Errorf("field [%s] does not support NULLs placement", Relevance)
+			}
+
+			if qb, err = orderByRelevance(d, model, tokens, qb, direction); err != nil {
+				return qb, err
+			}
+		} else {
+			return qb, fmt.Errorf("field [%s] is not tagged for sorting", sorter.Column)
+		}
+	}
+
+	if len(sorters) == 0 {
+		// Without explicit sorter order searched rows by relevance
+		if relevanceOrdered(d, model) {
+			var err error
+			if qb, err = orderByRelevance(d, model, tokens, qb, Desc); err != nil {
+				return qb, err
+			}
+		}
+
+		if ds, ok := interface{}(model).(DefaultSorters); ok && len(ds.DefaultSorters()) > 0 {
+			return orderBy(d, model, ds.DefaultSorters(), nil, qb)
+		}
+	}
+
+	// Unique key makes the order (and so the paging) stable
+	key := primaryKey(model)
+	if key == "" {
+		return qb, nil
+	}
+
+	// Column of the key is resolved as in selects (db tag or lower-cased field name)
+	key = lowerFirst(key)
+	for _, sorter := range sorters {
+		if taggedName(model, sorter.Column) == taggedName(model, key) {
+			return qb, nil
+		}
+	}
+
+	column, safe, err := columnExpression(d, model, key)
+	if err != nil {
+		return qb, err
+	}
+
+	return qb.OrderBy(fmt.Sprintf("%s %s", d.Name(column, safe), Asc)), nil
+}
+
+// Field tagged as `key` or Id, none when it is not selected within main query (`skip`, `relation`)
+func primaryKey(model Grid) string {
+	fType := reflect.TypeOf(model)
+	for i := 0; i < fType.NumField(); i++ {
+		if _, _, ok := fieldTag(fType.Field(i), uniqueKey); ok {
+			return selectableKey(fType.Field(i))
+		}
+	}
+
+	if field, ok := fType.FieldByName("Id"); ok {
+		return selectableKey(field)
+	}
+
+	return ""
+}
+
+func selectableKey(field reflect.StructField) string {
+	_, _, skipped := fieldTag(field, skip)
+	_, _, related := fieldTag(field, relation)
+	if skipped || related {
+		return ""
+	}
+
+	return field.Name
+}
+
+// Direction is never passed into query unchecked, ASC when missing
+// Placement of NULLs may follow (ASC_NULLS_LAST), dialect default is kept otherwise
+func sortDirection(sorter Sorter) (string, string, error) {
+	parts := strings.SplitN(strings.ToUpper(strings.TrimSpace(sorter.Direction)), "_NULLS_", 2)
+	direction := parts[0]
+	nulls := ""
+	if len(parts) == 2 {
+		nulls = parts[1]
+	}
+
+	if direction == "" && nulls == "" {
+		direction = Asc
+	}
+
+	if (direction == Asc || direction == Desc) && (nulls == "" || nulls == NullsFirst || nulls == NullsLast) {
+		return direction, nulls, nil
+	}
+
+	return "", "", fmt.Errorf("invalid direction [%s] of sorter [%s]", sorter.Direction, sorter.Column)
+}
+
+// Default placement of NULLs declared by `nulls=first|last` tag
+func nullsPlacement(model Grid, column string) (string, error) {
+	value, ok := tagValue(model, column, nullsOrder)
+	if !ok {
+		return "", nil
+	}
+
+	nulls := strings.ToUpper(strings.TrimSpace(value))
+	if nulls != NullsFirst && nulls != NullsLast {
+		return "", fmt.Errorf("invalid nulls [%s] of field [%s]", value, column)
+	}
+
+	return nulls, nil
+}
+
+// MariaDB lacks NULLS FIRST/LAST, sorting by ISNULL() emulates it
+func (d Dialect) orderBy(column, direction, nulls string) []string {
+	if nulls != NullsFirst && nulls != NullsLast {
+		return []string{fmt.Sprintf("%s %s", column, direction)}
+	}
+
+	if d == PostgreSQL {
+		return []string{fmt.Sprintf("%s %s NULLS %s", column, direction, nulls)}
+	}
+
+	isNull := Asc
+	if nulls == NullsFirst {
+		isNull = Desc
+	}
+
+	return []string{fmt.Sprintf("ISNULL(%s) %s", column, isNull), fmt.Sprintf("%s %s", column, direction)}
+}
@@ -0,0 +1,182 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sortGrid struct {
+	Id     int    `db:"o.id" grid:"sort"`
+	Status string `db:"o.status" grid:"sort"`
+	Note   string `db:"o.note"`
+	Closed string `db:"o.closed_at" grid:"sort,nulls=last"`
+}
+
+func (T sortGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("orders o")
+}
+
+func (T sortGrid) SortCallbacks() map[string]SortCallback {
+	return map[string]SortCallback{
+		"o.status": func(field, direction string) squirrel.Sqlizer {
+			return squirrel.Expr("FIELD("+field+", ?, ?, ?) "+direction, "new", "open", "closed")
+		},
+	}
+}
+
+func Test_OrderBy(t *testing.T) {
+	qb, err := orderBy(MySQL, sortGrid{}, []Sorter{
+		{Column: "status", Direction: "desc"},
+		{Column: "id"},
+	}, nil, squirrel.Select("*").From("orders o"))
+	require.Nil(t, err)
+
+	sql, args, err := qb.ToSql()
+	require.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM orders o ORDER BY FIELD(o.status, ?, ?, ?) DESC, `o`.`id` ASC", sql)
+	assert.Equal(t, []interface{}{"new", "open", "closed"}, args)
+
+	_, err = orderBy(MySQL, sortGrid{}, []Sorter{{Column: "note", Direction: "ASC"}}, nil, squirrel.Select("*"))
+	assert.EqualError(t, err, "field [note] is not tagged for sorting")
+
+	_, err = orderBy(MySQL, sortGrid{}, []Sorter{{Column: "id", Direction: "ASC; DROP TABLE orders"}}, nil, squirrel.Select("*"))
+	assert.EqualError(t, err, "invalid direction [ASC; DROP TABLE orders] of sorter [id]")
+}
+
+func Test_OrderByNulls(t *testing.T) {
+	sorters := []Sorter{
+		{Column: "id", Direction: "desc_nulls_first"},
+		{Column: "closed", Direction: "ASC"},
+		{Column: "closed", Direction: "DESC_NULLS_FIRST"},
+	}
+
+	qb, err := orderBy(MySQL, sortGrid{}, sorters, nil, squirrel.Select("*").From("orders o"))
+	require.Nil(t, err)
+
+	sql, _, err := qb.ToSql()
+	require.Nil(t, err)
+	assert.Equal(
+		t,
+		"SELECT * FROM orders o ORDER BY ISNULL(`o`.`id`) DESC, `o`.`id` DESC, "+
+			"ISNULL(`o`.`closed_at`) ASC, `o`.`closed_at` ASC, "+
+			"ISNULL(`o`.`closed_at`) DESC, `o`.`closed_at` DESC",
+		sql,
+	)
+
+	qb, err = orderBy(PostgreSQL, sortGrid{}, sorters, nil, squirrel.Select("*").From("orders o"))
+	require.Nil(t, err)
+
+	sql, _, err = qb.ToSql()
+	require.Nil(t, err)
+	assert.Equal(
+		t,
+		`SELECT * FROM orders o ORDER BY "o"."id" DESC NULLS FIRST, "o"."closed_at" ASC NULLS LAST, "o"."closed_at" DESC NULLS FIRST`,
+		sql,
+	)
+
+	_, err = orderBy(MySQL, sortGrid{}, []Sorter{{Column: "id", Direction: "ASC_NULLS_MIDDLE"}}, nil, squirrel.Select("*"))
+	assert.EqualError(t, err, "invalid direction [ASC_NULLS_MIDDLE] of sorter [id]")
+
+	_, err = orderBy(MySQL, sortGrid{}, []Sorter{{Column: "status", Direction: "ASC_NULLS_LAST"}}, nil, squirrel.Select("*"))
+	assert.EqualError(t, err, "field [status] sorted by callback does not support NULLs placement")
+
+	_, err = orderBy(MySQL, sortGrid{}, []Sorter{{Column: Relevance, Direction: "DESC_NULLS_LAST"}}, nil, squirrel.Select("*"))
+	assert.EqualError(t, err, "field [relevance] does not support NULLs placement")
+
+	_, err = orderBy(MySQL, invalidNullsGrid{}, []Sorter{{Column: "closed", Direction: "ASC"}}, nil, squirrel.Select("*"))
+	assert.EqualError(t, err, "invalid nulls [middle] of field [closed]")
+}
+
+type invalidNullsGrid struct {
+	Closed string `db:"o.closed_at" grid:"sort,nulls=middle"`
+}
+
+func (T invalidNullsGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("orders o")
+}
+
+type defaultSortGrid struct {
+	Code   string `db:"c.code" grid:"sort,key"`
+	Status string `db:"c.status" grid:"sort"`
+}
+
+func (T defaultSortGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("coupons c")
+}
+
+func (T defaultSortGrid) DefaultSorters() []Sorter {
+	return []Sorter{{Column: "status", Direction: Desc}}
+}
+
+type untaggedSortGrid struct {
+	Id   int    `grid:"sort"`
+	Name string `grid:"sort"`
+}
+
+func (T untaggedSortGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("users")
+}
+
+type skippedKeyGrid struct {
+	Id   int    `db:"t.id" grid:"relation=tag:t.file_id = f.id"`
+	Name string `db:"f.name" grid:"sort"`
+}
+
+func (T skippedKeyGrid) SearchQuery(qb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return qb.From("file f")
+}
+
+func Test_OrderByStable(t *testing.T) {
+	qb, err := orderBy(MySQL, sortGrid{}, []Sorter{{Column: "status", Direction: Asc}}, nil, squirrel.Select("*").From("orders o"))
+	require.Nil(t, err)
+
+	sql, _, err := qb.ToSql()
+	require.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM orders o ORDER BY FIELD(o.status, ?, ?, ?) ASC, `o`.`id` ASC", sql)
+
+	qb, err = orderBy(MySQL, sortGrid{}, nil, nil, squirrel.Select("*").From("orders o"))
+	require.Nil(t, err)
+
+	sql, _, err = qb.ToSql()
+	require.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM orders o ORDER BY `o`.`id` ASC", sql)
+
+	qb, err = orderBy(PostgreSQL, defaultSortGrid{}, nil, nil, squirrel.Select("*").From("coupons c"))
+	require.Nil(t, err)
+
+	sql, _, err = qb.ToSql()
+	require.Nil(t, err)
+	assert.Equal(t, `SELECT * FROM coupons c ORDER BY "c"."status" DESC, "c"."code" ASC`, sql)
+
+	qb, err = orderBy(PostgreSQL, defaultSortGrid{}, []Sorter{{Column: "code", Direction: Desc}}, nil, squirrel.Select("*").From("coupons c"))
+	require.Nil(t, err)
+
+	sql, _, err = qb.ToSql()
+	require.Nil(t, err)
+	assert.Equal(t, `SELECT * FROM coupons c ORDER BY "c"."code" DESC`, sql)
+
+	qb, err = orderBy(PostgreSQL, untaggedSortGrid{}, []Sorter{{Column: "name", Direction: Asc}}, nil, squirrel.Select("*").From("users"))
+	require.Nil(t, err)
+
+	sql, _, err = qb.ToSql()
+	require.Nil(t, err)
+	assert.Equal(t, `SELECT * FROM users ORDER BY "name" ASC, "id" ASC`, sql)
+
+	qb, err = orderBy(PostgreSQL, untaggedSortGrid{}, []Sorter{{Column: "id", Direction: Desc}}, nil, squirrel.Select("*").From("users"))
+	require.Nil(t, err)
+
+	sql, _, err = qb.ToSql()
+	require.Nil(t, err)
+	assert.Equal(t, `SELECT * FROM users ORDER BY "id" DESC`, sql)
+
+	// Key not selected within main query is no tiebreaker
+	qb, err = orderBy(MySQL, skippedKeyGrid{}, []Sorter{{Column: "name", Direction: Asc}}, nil, squirrel.Select("*").From("file f"))
+	require.Nil(t, err)
+
+	sql, _, err = qb.ToSql()
+	require.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM file f ORDER BY `f`.`name` ASC", sql)
+}